@@ -2,30 +2,36 @@ package main
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"log"
 	"net"
 	"net/http"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/dipress/cards/internal/card"
-	"github.com/dipress/cards/internal/storage/postgres"
+	"github.com/dipress/cards/internal/storage/cache"
+	"github.com/dipress/cards/internal/storage/memory"
+	"github.com/prometheus/client_golang/prometheus"
 )
 
 func TestCreateCard(t *testing.T) {
 	t.Log("with prepred server")
 	{
-		db, teardown := postgresDB(t)
-		defer teardown()
+		cardRepo := memory.NewCardRepository()
 
 		lis, err := net.Listen("tcp", "127.0.0.1:0")
 		if err != nil {
 			log.Fatalf("failed to listen: %v", err)
 		}
 
-		services := setupServices(db)
-		s := setupServer(lis.Addr().String(), nil, services)
+		services, err := setupServices(cardRepo, nil, prometheus.NewRegistry())
+		if err != nil {
+			log.Fatalf("failed to setup services: %v", err)
+		}
+		s := setupServer(lis.Addr().String(), testLogger(), services)
 		go s.Serve(lis)
 		defer s.Close()
 
@@ -83,8 +89,7 @@ func TestCreateCard(t *testing.T) {
 func TestFindCard(t *testing.T) {
 	t.Log("with prepred server")
 	{
-		db, teardown := postgresDB(t)
-		defer teardown()
+		cardRepo := memory.NewCardRepository()
 
 		lis, err := net.Listen("tcp", "127.0.0.1:0")
 		if err != nil {
@@ -94,8 +99,6 @@ func TestFindCard(t *testing.T) {
 		ctx, cancel := context.WithTimeout(context.Background(), caseTimeout)
 		defer cancel()
 
-		cardRepo := postgres.NewCardRepository(db)
-
 		nc := card.NewCard{
 			Word:          "depict",
 			Transcription: "diˈpikt",
@@ -108,8 +111,11 @@ func TestFindCard(t *testing.T) {
 			t.Errorf("unexpected error: %v", err)
 		}
 
-		services := setupServices(db)
-		s := setupServer(lis.Addr().String(), nil, services)
+		services, err := setupServices(cardRepo, nil, prometheus.NewRegistry())
+		if err != nil {
+			log.Fatalf("failed to setup services: %v", err)
+		}
+		s := setupServer(lis.Addr().String(), testLogger(), services)
 		go s.Serve(lis)
 		defer s.Close()
 
@@ -158,8 +164,7 @@ func TestFindCard(t *testing.T) {
 func TestUpdateCard(t *testing.T) {
 	t.Log("with prepred server")
 	{
-		db, teardown := postgresDB(t)
-		defer teardown()
+		cardRepo := memory.NewCardRepository()
 
 		lis, err := net.Listen("tcp", "127.0.0.1:0")
 		if err != nil {
@@ -169,8 +174,6 @@ func TestUpdateCard(t *testing.T) {
 		ctx, cancel := context.WithTimeout(context.Background(), caseTimeout)
 		defer cancel()
 
-		cardRepo := postgres.NewCardRepository(db)
-
 		nc := card.NewCard{
 			Word:          "pitfall",
 			Transcription: "ˈpitˌfôl",
@@ -183,13 +186,22 @@ func TestUpdateCard(t *testing.T) {
 			t.Errorf("unexpected error: %v", err)
 		}
 
-		services := setupServices(db)
-		s := setupServer(lis.Addr().String(), nil, services)
+		services, err := setupServices(cardRepo, cache.NewLRU(10, time.Minute), prometheus.NewRegistry())
+		if err != nil {
+			log.Fatalf("failed to setup services: %v", err)
+		}
+		s := setupServer(lis.Addr().String(), testLogger(), services)
 		go s.Serve(lis)
 		defer s.Close()
 
-		t.Log("\ttest:0\tshould update a card.")
+		t.Log("\ttest:0\tshould update a cached card.")
 		{
+			resp, err := http.Get(fmt.Sprintf("http://%s/api/v1/cards/%d", s.Addr, cd.ID))
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			resp.Body.Close()
+
 			cardStr := `{
 				"word": "pitfall", 
 				"transcription": "ˈpitˌfôl", 
@@ -205,7 +217,7 @@ func TestUpdateCard(t *testing.T) {
 				t.Fatalf("unexpected error: %v", err)
 			}
 
-			resp, err := http.DefaultClient.Do(req)
+			resp, err = http.DefaultClient.Do(req)
 			if err != nil {
 				t.Errorf("unexpected error: %v", err)
 			}
@@ -213,6 +225,21 @@ func TestUpdateCard(t *testing.T) {
 			if resp.StatusCode != http.StatusOK {
 				t.Errorf("unexpected status code: %d expected: %d", resp.StatusCode, http.StatusOK)
 			}
+
+			resp, err = http.Get(fmt.Sprintf("http://%s/api/v1/cards/%d", s.Addr, cd.ID))
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			defer resp.Body.Close()
+
+			var got card.Card
+			if err := json.NewDecoder(resp.Body).Decode(&got); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if got.Translation != "западня" {
+				t.Errorf("unexpected translation: %s expected: %s", got.Translation, "западня")
+			}
 		}
 
 		t.Log("\ttest:1\tshould get a validation error")
@@ -273,8 +300,7 @@ func TestUpdateCard(t *testing.T) {
 func TestDeleteCard(t *testing.T) {
 	t.Log("with prepred server")
 	{
-		db, teardown := postgresDB(t)
-		defer teardown()
+		cardRepo := memory.NewCardRepository()
 
 		lis, err := net.Listen("tcp", "127.0.0.1:0")
 		if err != nil {
@@ -284,8 +310,6 @@ func TestDeleteCard(t *testing.T) {
 		ctx, cancel := context.WithTimeout(context.Background(), caseTimeout)
 		defer cancel()
 
-		cardRepo := postgres.NewCardRepository(db)
-
 		nc := card.NewCard{
 			Word:          "own",
 			Transcription: "ōn",
@@ -298,8 +322,11 @@ func TestDeleteCard(t *testing.T) {
 			t.Errorf("unexpected error: %v", err)
 		}
 
-		services := setupServices(db)
-		s := setupServer(lis.Addr().String(), nil, services)
+		services, err := setupServices(cardRepo, nil, prometheus.NewRegistry())
+		if err != nil {
+			log.Fatalf("failed to setup services: %v", err)
+		}
+		s := setupServer(lis.Addr().String(), testLogger(), services)
 		go s.Serve(lis)
 		defer s.Close()
 
@@ -5,103 +5,449 @@ import (
 	"database/sql"
 	"flag"
 	"fmt"
+	"io"
+	"log"
+	"net"
 	"net/http"
 	"os"
 	"os/signal"
+	"strings"
 	"syscall"
+	"time"
 
 	httpBroker "github.com/dipress/cards/internal/broker/http"
-	"github.com/dipress/cards/internal/broker/http/logger"
+	"github.com/dipress/cards/internal/broker/http/health"
 	"github.com/dipress/cards/internal/card"
-	"github.com/dipress/cards/internal/storage/postgres"
-	"github.com/dipress/cards/internal/storage/postgres/schema"
+	"github.com/dipress/cards/internal/config"
+	"github.com/dipress/cards/internal/kit/logger"
+	"github.com/dipress/cards/internal/kit/ratelimit"
+	"github.com/dipress/cards/internal/metrics"
+	"github.com/dipress/cards/internal/storage/cache"
+	"github.com/dipress/cards/internal/tracing"
 	"github.com/dipress/cards/internal/validation"
-	"github.com/mattes/migrate"
+	_ "github.com/lib/pq"
 	"github.com/pkg/errors"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/sirupsen/logrus"
 )
 
+const (
+	// exitOK means the server stopped gracefully.
+	exitOK = 0
+	// exitError means the server failed to start or serve.
+	exitError = 1
+	// exitShutdown means in-flight requests were cancelled
+	// because they didn't finish in the shutdown timeout.
+	exitShutdown = 2
+)
+
+// exposedHeaders are readable by browser clients from other origins.
+var exposedHeaders = []string{
+	httpBroker.RequestIDHeader,
+	"RateLimit-Limit",
+	"RateLimit-Remaining",
+	"RateLimit-Reset",
+	"Retry-After",
+}
+
+// replicaCheckInterval is how often replicas are pinged.
+const replicaCheckInterval = 10 * time.Second
+
+// listFlag collects values of a repeated flag.
+type listFlag []string
+
+func (f *listFlag) String() string {
+	return strings.Join(*f, ",")
+}
+
+func (f *listFlag) Set(v string) error {
+	*f = append(*f, v)
+	return nil
+}
+
 func main() {
+	os.Exit(run())
+}
+
+func run() int {
 	var (
-		addr = flag.String("addr", ":8080", "address of http server")
-		dsn  = flag.String("dsn", "", "postgres database DSN")
+		configPath  = flag.String("config", os.Getenv("CARDS_CONFIG"), "path to yaml or toml config file (CARDS_CONFIG)")
+		addr        = flag.String("addr", "", "address of http server, overrides config")
+		dsn         = flag.String("dsn", "", "database DSN, sqlite://path selects SQLite, overrides config")
+		printConfig = flag.Bool("print-config", false, "print effective config and exit")
+		autoMigrate = flag.Bool("migrate", true, "apply pending migrations on start, disable to run cards migrate as a separate step")
+		replicaDSNs listFlag
 	)
 
+	flag.Var(&replicaDSNs, "replica-dsn", "postgres read replica DSN, repeat for several replicas, overrides config")
+
 	flag.Parse()
 
+	if flag.Arg(0) == "migrate" {
+		if code, ok := runCreateMigration(flag.Args()[1:], os.Stdout, os.Stderr); ok {
+			return code
+		}
+	}
+
+	// Load config, flags take precedence over file and environment.
+	cfg, err := config.Load(*configPath)
+	if err != nil {
+		log.Printf("failed to load config: %v", err)
+		return exitError
+	}
+
+	flag.Visit(func(f *flag.Flag) {
+		switch f.Name {
+		case "addr":
+			cfg.Server.Addr = *addr
+		case "dsn":
+			cfg.Database.DSN = *dsn
+		case "replica-dsn":
+			cfg.Database.ReplicaDSNs = replicaDSNs
+		}
+	})
+
+	if *printConfig {
+		data, err := cfg.Dump()
+		if err != nil {
+			log.Printf("failed to dump config: %v", err)
+			return exitError
+		}
+		os.Stdout.Write(data)
+	}
+
+	if err := cfg.Validate(); err != nil {
+		log.Printf("invalid config: %v", err)
+		return exitError
+	}
+
+	if *printConfig {
+		return exitOK
+	}
+
+	switch {
+	case flag.Arg(0) == "migrate":
+		return runMigrate(cfg, flag.Args()[1:], os.Stdout, os.Stderr)
+	case flag.NArg() > 0:
+		log.Printf("unknown command %q, available: migrate", flag.Arg(0))
+		return exitError
+	}
+
 	// Logger initialize.
-	logger, err := logger.New(
-		logger.WithSensitiveFields([]string{
-			"password",
-			"token",
-		}),
-	)
+	logger, err := setupLogger(&cfg.Log)
+	if err != nil {
+		log.Printf("failed to setup logger: %v", err)
+		return exitError
+	}
+
+	// Tracing.
+	shutdownTracing, err := tracing.Setup(context.Background(), &cfg.Tracing)
+	if err != nil {
+		logger.Error(fmt.Errorf("failed to setup tracing: %w", err), nil)
+		return exitError
+	}
+	defer func() {
+		ctx, cancel := context.WithTimeout(context.Background(), cfg.Server.ShutdownTimeout.Duration)
+		defer cancel()
+
+		if err := shutdownTracing(ctx); err != nil {
+			logger.Error(err, nil)
+		}
+	}()
 
 	// Setup database connection.
 	logger.Info("connecting to db", nil)
-	db, err := sql.Open("postgres", *dsn)
+	store, err := openStorage(cfg.Database.DSN, cfg.Database.ReplicaDSNs)
 	if err != nil {
-		logger.Fatal(fmt.Errorf("failed to create db: %w", err), nil)
+		logger.Error(fmt.Errorf("failed to create db: %w", err), nil)
+		return exitError
+	}
+
+	db := store.db
+	for _, d := range append([]*sql.DB{db}, store.replicas...) {
+		d.SetMaxOpenConns(cfg.Database.MaxOpenConns)
+		d.SetMaxIdleConns(cfg.Database.MaxIdleConns)
+		d.SetConnMaxLifetime(cfg.Database.ConnMaxLifetime.Duration)
+		d.SetConnMaxIdleTime(cfg.Database.ConnMaxIdleTime.Duration)
 	}
 
 	if err := db.Ping(); err != nil {
-		logger.Fatal(fmt.Errorf("failed to connect db: %w", err), nil)
+		store.Close()
+		logger.Error(fmt.Errorf("failed to connect db: %w", err), nil)
+		return exitError
 	}
 
-	defer db.Close()
-	logger.Info("connection to db established", nil)
+	logger.Info("connection to db established", map[string]interface{}{
+		"storage":  store.name,
+		"replicas": len(store.replicas),
+	})
+
+	// Replicas which fail get no reads until they recover,
+	// the primary serves reads meanwhile.
+	if store.checkReplicas != nil {
+		checkCtx, stopChecks := context.WithCancel(context.Background())
+		defer stopChecks()
 
-	// Migrate schema.
-	if err := schema.Migrate(db); err != nil {
-		if errors.Is(err, migrate.ErrNoChange) {
-			logger.Fatal(fmt.Errorf("failed to migrate schema: %w", err), nil)
+		go checkReplicas(checkCtx, logger, store.checkReplicas, replicaCheckInterval)
+	}
+
+	// Migrate schema, otherwise the migrations health check
+	// keeps the server not ready until the schema is migrated.
+	migrated := true
+	if *autoMigrate {
+		if err := store.migrate(); err != nil {
+			store.Close()
+			logger.Error(fmt.Errorf("failed to migrate schema: %w", err), nil)
+			return exitError
+		}
+	} else if err := store.check(context.Background()); err != nil {
+		logger.Warn(fmt.Sprintf("schema isn't migrated: %v", err), nil)
+		migrated = false
+	}
+
+	// Statements are prepared once the tables exist,
+	// the repository prepares them on first use otherwise.
+	if store.prepare != nil && migrated {
+		if err := store.prepare(context.Background()); err != nil {
+			store.Close()
+			logger.Error(fmt.Errorf("failed to prepare statements: %w", err), nil)
+			return exitError
 		}
 	}
 
 	// Make a channel for errors.
-	errChan := make(chan error)
+	errChan := make(chan error, 1)
+
+	// Metrics.
+	reg := prometheus.NewRegistry()
+	reg.MustRegister(
+		prometheus.NewGoCollector(),
+		prometheus.NewProcessCollector(prometheus.ProcessCollectorOpts{}),
+		metrics.NewDBStatsCollector(db, "primary"),
+	)
+
+	for i, replica := range store.replicas {
+		reg.MustRegister(metrics.NewDBStatsCollector(replica, fmt.Sprintf("replica_%d", i+1)))
+	}
 
 	// Services
-	services := setupServices(db)
+	var serviceOptions []card.ServiceOption
+	if store.transactor != nil {
+		serviceOptions = append(serviceOptions, card.WithTransactor(store.transactor))
+	}
+
+	var cardCache cache.Store
+	if cfg.Cache.Enabled {
+		cardCache = cache.NewLRU(cfg.Cache.Size, cfg.Cache.TTL.Duration)
+	}
+
+	services, err := setupServices(store.cards, cardCache, reg, serviceOptions...)
+	if err != nil {
+		store.Close()
+		logger.Error(fmt.Errorf("failed to setup services: %w", err), nil)
+		return exitError
+	}
+
+	// Requests are cancelled with this context
+	// when they outlive the shutdown timeout.
+	requestsCtx, cancelRequests := context.WithCancel(context.Background())
+	defer cancelRequests()
+
+	var readiness httpBroker.Readiness
+
+	options := []httpBroker.Option{
+		httpBroker.WithTimeouts(
+			cfg.Server.ReadTimeout.Duration,
+			cfg.Server.WriteTimeout.Duration,
+			cfg.Server.IdleTimeout.Duration,
+		),
+		httpBroker.WithBaseContext(requestsCtx),
+		httpBroker.WithReadiness(&readiness),
+		httpBroker.WithMetrics(reg),
+		httpBroker.WithHealthCheck("database", health.CheckerFunc(db.PingContext)),
+		httpBroker.WithHealthCheck("migrations", health.CheckerFunc(store.check)),
+	}
+
+	// Reads of a request which changed a card go to the primary.
+	if store.requestContext != nil {
+		options = append(options, httpBroker.WithRequestContext(store.requestContext))
+	}
+
+	if cfg.RateLimit.Enabled {
+		options = append(options, httpBroker.WithRateLimit(ratelimit.NewMemoryStore(),
+			ratelimit.Limit{Rate: cfg.RateLimit.ReadRate, Burst: cfg.RateLimit.ReadBurst},
+			ratelimit.Limit{Rate: cfg.RateLimit.WriteRate, Burst: cfg.RateLimit.WriteBurst},
+		))
+	}
+
+	if cfg.Compression.Enabled {
+		options = append(options, httpBroker.WithCompression(cfg.Compression.MinSize))
+	}
+
+	if len(cfg.CORS.AllowedOrigins) > 0 {
+		options = append(options, httpBroker.WithCORS(httpBroker.CORS{
+			AllowedOrigins:   cfg.CORS.AllowedOrigins,
+			AllowedMethods:   cfg.CORS.AllowedMethods,
+			AllowedHeaders:   cfg.CORS.AllowedHeaders,
+			ExposedHeaders:   exposedHeaders,
+			AllowCredentials: cfg.CORS.AllowCredentials,
+			MaxAge:           cfg.CORS.MaxAge.Duration,
+		}))
+	}
 
 	// Setup server.
-	srv := setupServer(*addr, logger, services)
+	srv := setupServer(cfg.Server.Addr, logger, services, options...)
+
+	lis, err := net.Listen("tcp", srv.Addr)
+	if err != nil {
+		store.Close()
+		logger.Error(fmt.Errorf("listen %s: %w", srv.Addr, err), nil)
+		return exitError
+	}
 
 	go func() {
 		logger.Info(fmt.Sprintf("starting %s server", srv.Addr), nil)
-		if err := srv.ListenAndServe(); err != nil {
+		if err := srv.Serve(lis); err != nil && !errors.Is(err, http.ErrServerClosed) {
 			errChan <- fmt.Errorf("launch server %s: %w", srv.Addr, err)
 		}
 	}()
 
+	readiness.SetReady(true)
+
 	// Make a channel to listen for an interrupt or terminate signal from the OS.
 	// Use a buffered channel because the signal package requires it.
 	osSignals := make(chan os.Signal, 1)
 	signal.Notify(osSignals, os.Interrupt, syscall.SIGTERM)
 
+	code := exitOK
+
 	select {
 	case err := <-errChan:
-		logger.Fatal(err, nil)
-	case <-osSignals:
-		if err := srv.Shutdown(context.TODO()); err != nil {
-			logger.Fatal(fmt.Errorf("stop server %s: %w", srv.Addr, err), nil)
+		logger.Error(err, nil)
+		code = exitError
+	case sig := <-osSignals:
+		logger.Info(fmt.Sprintf("received %s, shutting down", sig), map[string]interface{}{
+			"drain_period":     cfg.Server.DrainPeriod.String(),
+			"shutdown_timeout": cfg.Server.ShutdownTimeout.String(),
+		})
+	}
+
+	if err := shutdown(srv, &readiness, cancelRequests, store, cfg.Server.DrainPeriod.Duration, cfg.Server.ShutdownTimeout.Duration); err != nil {
+		logger.Error(fmt.Errorf("shutdown: %w", err), nil)
+		if code == exitOK {
+			code = exitShutdown
+		}
+	}
+
+	logger.Info("server stopped", map[string]interface{}{
+		"exit_code": code,
+	})
+
+	return code
+}
+
+// shutdown stops the server in order: it marks the server as not ready,
+// keeps serving for the drain period so that load balancers notice,
+// waits for in-flight requests until the timeout, cancels contexts
+// of the requests which are still running and closes the database.
+func shutdown(srv *http.Server, readiness *httpBroker.Readiness, cancelRequests context.CancelFunc, db io.Closer, drain, timeout time.Duration) error {
+	readiness.SetReady(false)
+	srv.SetKeepAlivesEnabled(false)
+
+	time.Sleep(drain)
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	var errs []string
+
+	err := srv.Shutdown(ctx)
+
+	// Cancel requests which outlived the timeout.
+	cancelRequests()
+
+	if err != nil {
+		errs = append(errs, fmt.Sprintf("stop server %s: %v", srv.Addr, err))
+
+		if err := srv.Close(); err != nil {
+			errs = append(errs, fmt.Sprintf("close server %s: %v", srv.Addr, err))
 		}
 	}
+
+	if err := db.Close(); err != nil {
+		errs = append(errs, fmt.Sprintf("close db: %v", err))
+	}
+
+	if len(errs) > 0 {
+		return errors.New(strings.Join(errs, "; "))
+	}
+
+	return nil
 }
 
-func setupServer(addr string, logger *logger.Logger, services *httpBroker.Services) *http.Server {
-	return httpBroker.NewServer(addr, logger, services)
+// checkReplicas pings the replicas every interval until ctx is done.
+func checkReplicas(ctx context.Context, logger *logger.Logger, check func(ctx context.Context) error, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if err := check(ctx); err != nil && ctx.Err() == nil {
+			logger.Warn(fmt.Sprintf("check replicas: %v", err), nil)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
 }
 
-func setupServices(db *sql.DB) *httpBroker.Services {
+func setupLogger(cfg *config.Log) (*logger.Logger, error) {
+	level, err := logrus.ParseLevel(cfg.Level)
+	if err != nil {
+		return nil, fmt.Errorf("parse level: %w", err)
+	}
+
+	var formatter logrus.Formatter = &logrus.JSONFormatter{}
+	if cfg.Format == config.FormatText {
+		formatter = &logrus.TextFormatter{}
+	}
+
+	return logger.New(
+		logger.WithSensitiveFields(cfg.SensitiveFields),
+		logger.SetFormatter(formatter),
+		logger.SetLevel(level),
+	)
+}
+
+func setupServer(addr string, logger *logger.Logger, services *httpBroker.Services, options ...httpBroker.Option) *http.Server {
+	return httpBroker.NewServer(addr, logger, services, options...)
+}
+
+// setupServices decorates the repository, a nil store leaves card reads uncached.
+func setupServices(cards card.Repository, cardCache cache.Store, reg prometheus.Registerer, opts ...card.ServiceOption) (*httpBroker.Services, error) {
 	// Repositories.
-	cardRepo := postgres.NewCardRepository(db)
+	cardRepo, err := metrics.NewCardRepository(cards, reg)
+	if err != nil {
+		return nil, fmt.Errorf("card repository metrics: %w", err)
+	}
+
+	if cardCache != nil {
+		if cardRepo, err = cache.NewCardRepository(cardRepo, cardCache, reg); err != nil {
+			return nil, fmt.Errorf("card repository cache: %w", err)
+		}
+	}
 
 	// Servives.
-	cardService := card.NewService(cardRepo, &validation.Card{})
+	cardService, err := metrics.NewCardService(tracing.NewCardService(card.NewService(cardRepo, &validation.Card{}, opts...)), reg)
+	if err != nil {
+		return nil, fmt.Errorf("card service metrics: %w", err)
+	}
 
 	services := httpBroker.Services{
 		Card: cardService,
 	}
 
-	return &services
+	return &services, nil
 }
@@ -1,73 +1,22 @@
 package main
 
 import (
-	"database/sql"
-	"flag"
-	"fmt"
+	"io/ioutil"
 	"log"
-	"os"
-	"testing"
 	"time"
 
-	"github.com/DATA-DOG/go-txdb"
-	"github.com/dipress/cards/internal/kit/docker"
-	"github.com/dipress/cards/internal/storage/postgres/schema"
-	"github.com/ory/dockertest"
+	"github.com/dipress/cards/internal/kit/logger"
 )
 
 const (
 	caseTimeout = 5 * time.Second
 )
 
-var (
-	db *sql.DB
-)
-
-func TestMain(m *testing.M) {
-	flag.Parse()
-
-	if testing.Short() {
-		os.Exit(m.Run())
-	}
-
-	pool, err := dockertest.NewPool("")
-	if err != nil {
-		log.Fatalf("could not connect to docker: %v", err)
-	}
-
-	pgDocker, err := docker.NewPostgres(pool)
-	if err != nil {
-		log.Fatalf("prepare postgres with docker: %v", err)
-	}
-
-	db = pgDocker.DB
-
-	if err := schema.Migrate(db); err != nil {
-		log.Fatalf("migrate schema: %v", err)
-	}
-
-	txdb.Register("pgsqltx", "postgres",
-		fmt.Sprintf("password=test user=test dbname=test host=localhost port=%s sslmode=disable",
-			pgDocker.Resource.GetPort("5432/tcp")),
-	)
-
-	code := m.Run()
-
-	db.Close()
-	if err := pool.Purge(pgDocker.Resource); err != nil {
-		log.Fatalf("could not purge postgres docker: %v", err)
-	}
-
-	os.Exit(code)
-}
-
-func postgresDB(t *testing.T) (db *sql.DB, teardown func() error) {
-	dbName := fmt.Sprintf("db_%d", time.Now().UnixNano())
-	db, err := sql.Open("pgsqltx", dbName)
-
+func testLogger() *logger.Logger {
+	l, err := logger.New(logger.SetOutput(ioutil.Discard))
 	if err != nil {
-		t.Fatalf("open postgres tx connection: %s", err)
+		log.Fatalf("failed to create logger: %v", err)
 	}
 
-	return db, db.Close
+	return l
 }
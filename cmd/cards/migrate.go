@@ -0,0 +1,303 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"strconv"
+	"time"
+
+	"github.com/dipress/cards/internal/config"
+	"github.com/dipress/cards/internal/storage/migration"
+)
+
+const migrateUsage = `Usage: cards [flags] migrate [-dry-run] [-dir path] <command> [arguments]
+
+Commands:
+  up             apply all pending migrations
+  down N         roll back N migrations
+  goto VERSION   migrate up or down to the version
+  status         print applied and pending migrations
+  force VERSION  set the version without running migrations and clear
+                 the dirty flag, -1 means no migration is applied
+  create NAME    create empty up and down files of a new migration in
+                 the -dir source directory, migrations are embedded in
+                 the binary so it's a development command which
+                 needs no config or database
+`
+
+// errUsage raises when command line arguments are invalid.
+var errUsage = errors.New("invalid usage")
+
+// runMigrate changes the schema as a separate step of a deployment,
+// the server is started with -migrate=false then.
+func runMigrate(cfg *config.Config, args []string, stdout, stderr io.Writer) int {
+	if _, err := parseMigrate(args); err != nil {
+		return migrateFailed(err, stderr)
+	}
+
+	store, err := openStorage(cfg.Database.DSN, nil)
+	if err != nil {
+		fmt.Fprintf(stderr, "cards migrate: open storage: %v\n", err)
+		return exitError
+	}
+	defer store.Close()
+
+	if err := migrateCommand(store, args, stdout); err != nil {
+		return migrateFailed(err, stderr)
+	}
+
+	return exitOK
+}
+
+// runCreateMigration runs the migrate command when it's create, which
+// only writes source files and needs neither the config nor the
+// database. It reports false when the command is another one.
+func runCreateMigration(args []string, stdout, stderr io.Writer) (int, bool) {
+	m, err := parseMigrate(args)
+	if err != nil || m.cmd != "create" {
+		return 0, false
+	}
+
+	if err := createMigration(m.dir, m.args, m.dryRun, stdout); err != nil {
+		return migrateFailed(err, stderr), true
+	}
+
+	return exitOK, true
+}
+
+func migrateFailed(err error, stderr io.Writer) int {
+	if errors.Is(err, errUsage) {
+		fmt.Fprintf(stderr, "cards migrate: %v\n\n%s", err, migrateUsage)
+		return exitError
+	}
+
+	fmt.Fprintf(stderr, "cards migrate: %v\n", err)
+	return exitError
+}
+
+// migrateArgs are the parsed arguments of the migrate command.
+type migrateArgs struct {
+	dryRun bool
+	dir    string
+	cmd    string
+	args   []string
+}
+
+func parseMigrate(args []string) (migrateArgs, error) {
+	fs := flag.NewFlagSet("migrate", flag.ContinueOnError)
+	fs.SetOutput(ioutil.Discard)
+
+	dryRun := fs.Bool("dry-run", false, "print what would be done without changing anything")
+	dir := fs.String("dir", "", "source directory new migrations are created in")
+
+	if err := fs.Parse(args); err != nil {
+		return migrateArgs{}, fmt.Errorf("parse flags: %v: %w", err, errUsage)
+	}
+
+	if fs.NArg() == 0 {
+		return migrateArgs{}, fmt.Errorf("command is required: %w", errUsage)
+	}
+
+	m := migrateArgs{
+		dryRun: *dryRun,
+		dir:    *dir,
+		cmd:    fs.Arg(0),
+		args:   fs.Args()[1:],
+	}
+
+	return m, nil
+}
+
+func migrateCommand(store *storage, args []string, w io.Writer) error {
+	m, err := parseMigrate(args)
+	if err != nil {
+		return err
+	}
+
+	switch m.cmd {
+	case "create":
+		return createMigration(m.dir, m.args, m.dryRun, w)
+	case "status":
+		return printStatus(store.schema, w)
+	case "force":
+		return forceVersion(store.schema, m.args, m.dryRun, w)
+	case "up", "down", "goto":
+		return migrateSchema(store.schema, m.cmd, m.args, m.dryRun, w)
+	}
+
+	return fmt.Errorf("unknown command %q: %w", m.cmd, errUsage)
+}
+
+func createMigration(dir string, args []string, dryRun bool, w io.Writer) error {
+	if len(args) != 1 {
+		return fmt.Errorf("migration name is required: %w", errUsage)
+	}
+
+	if dir == "" {
+		return fmt.Errorf("-dir is required: %w", errUsage)
+	}
+
+	if dryRun {
+		fmt.Fprintf(w, "would create %s_%s up and down files in %s\n", time.Now().UTC().Format(migration.VersionLayout), args[0], dir)
+		return nil
+	}
+
+	up, down, err := migration.Create(dir, args[0], time.Now())
+	if err != nil {
+		return fmt.Errorf("create: %w", err)
+	}
+
+	fmt.Fprintf(w, "created %s\ncreated %s\n", up, down)
+
+	return nil
+}
+
+func printStatus(m migration.Migrator, w io.Writer) error {
+	ms, err := m.Migrations()
+	if err != nil {
+		return fmt.Errorf("migrations: %w", err)
+	}
+
+	version, dirty, err := m.Version()
+	if err != nil {
+		return fmt.Errorf("version: %w", err)
+	}
+
+	if dirty {
+		fmt.Fprintf(w, "version %d (dirty)\n", version)
+	} else {
+		fmt.Fprintf(w, "version %d\n", version)
+	}
+
+	for _, mi := range ms {
+		state := "pending"
+		if mi.Version <= version {
+			state = "applied"
+		}
+
+		fmt.Fprintf(w, "%-8s %s\n", state, mi)
+	}
+
+	return nil
+}
+
+func forceVersion(m migration.Migrator, args []string, dryRun bool, w io.Writer) error {
+	if len(args) != 1 {
+		return fmt.Errorf("version is required: %w", errUsage)
+	}
+
+	version, err := strconv.Atoi(args[0])
+	if err != nil || version < -1 {
+		return fmt.Errorf("invalid version %q: %w", args[0], errUsage)
+	}
+
+	if dryRun {
+		fmt.Fprintf(w, "would force version %d\n", version)
+		return nil
+	}
+
+	if err := m.Force(version); err != nil {
+		return fmt.Errorf("force: %w", err)
+	}
+
+	fmt.Fprintf(w, "forced version %d\n", version)
+
+	return nil
+}
+
+// migrateSchema prints the migrations which move the schema to the
+// target of the command and runs them unless it's a dry run.
+func migrateSchema(m migration.Migrator, cmd string, args []string, dryRun bool, w io.Writer) error {
+	ms, err := m.Migrations()
+	if err != nil {
+		return fmt.Errorf("migrations: %w", err)
+	}
+
+	current, dirty, err := m.Version()
+	if err != nil {
+		return fmt.Errorf("version: %w", err)
+	}
+
+	if dirty {
+		return fmt.Errorf("schema version %d is dirty, fix the schema and run force", current)
+	}
+
+	var (
+		target uint
+		run    func() error
+	)
+
+	switch cmd {
+	case "up":
+		if len(args) != 0 {
+			return fmt.Errorf("up takes no arguments: %w", errUsage)
+		}
+
+		target, run = migration.Latest(ms), m.Up
+	case "down":
+		if len(args) != 1 {
+			return fmt.Errorf("number of migrations is required: %w", errUsage)
+		}
+
+		n, err := strconv.Atoi(args[0])
+		if err != nil || n < 1 {
+			return fmt.Errorf("invalid number of migrations %q: %w", args[0], errUsage)
+		}
+
+		if target, err = migration.StepsTarget(ms, current, -n); err != nil {
+			return fmt.Errorf("steps target: %w", err)
+		}
+
+		run = func() error { return m.Steps(-n) }
+	case "goto":
+		if len(args) != 1 {
+			return fmt.Errorf("version is required: %w", errUsage)
+		}
+
+		v, err := strconv.ParseUint(args[0], 10, 64)
+		if err != nil {
+			return fmt.Errorf("invalid version %q: %w", args[0], errUsage)
+		}
+
+		if _, ok := migration.Find(ms, uint(v)); !ok {
+			return fmt.Errorf("no migration of version %d", v)
+		}
+
+		target = uint(v)
+		run = func() error { return m.Goto(target) }
+	}
+
+	up, steps := migration.Plan(ms, current, target)
+	if len(steps) == 0 {
+		fmt.Fprintf(w, "no change, version %d\n", current)
+		return nil
+	}
+
+	direction := "down"
+	if up {
+		direction = "up"
+	}
+
+	if dryRun {
+		fmt.Fprintf(w, "dry run, nothing is applied\n")
+	}
+
+	for _, mi := range steps {
+		fmt.Fprintf(w, "%-4s %s\n", direction, mi)
+	}
+
+	if dryRun {
+		return nil
+	}
+
+	if err := run(); err != nil {
+		return fmt.Errorf("%s: %w", cmd, err)
+	}
+
+	fmt.Fprintf(w, "version %d\n", target)
+
+	return nil
+}
@@ -0,0 +1,168 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMigrateCommand(t *testing.T) {
+	t.Log("with a fresh sqlite database")
+	{
+		store, err := openStorage(sqliteScheme+filepath.Join(t.TempDir(), "cards.db"), nil)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		defer store.Close()
+
+		run := func(args ...string) (string, error) {
+			var out bytes.Buffer
+			err := migrateCommand(store, args, &out)
+			return out.String(), err
+		}
+
+		t.Log("\ttest:0\tshould show pending migrations")
+		{
+			out, err := run("status")
+			assert.Nil(t, err)
+			assert.Equal(t, "version 0\n"+
+				"pending  20200228130253_cards\n"+
+				"pending  20200305183012_card_answers\n", out)
+		}
+
+		t.Log("\ttest:1\tshould not apply migrations in a dry run")
+		{
+			out, err := run("-dry-run", "up")
+			assert.Nil(t, err)
+			assert.Equal(t, "dry run, nothing is applied\n"+
+				"up   20200228130253_cards\n"+
+				"up   20200305183012_card_answers\n", out)
+
+			version, _, err := store.schema.Version()
+			assert.Nil(t, err)
+			assert.Equal(t, uint(0), version)
+		}
+
+		t.Log("\ttest:2\tshould apply migrations")
+		{
+			out, err := run("up")
+			assert.Nil(t, err)
+			assert.Contains(t, out, "version 20200305183012\n")
+
+			out, err = run("up")
+			assert.Nil(t, err)
+			assert.Equal(t, "no change, version 20200305183012\n", out)
+		}
+
+		t.Log("\ttest:3\tshould roll back migrations")
+		{
+			out, err := run("down", "1")
+			assert.Nil(t, err)
+			assert.Equal(t, "down 20200305183012_card_answers\nversion 20200228130253\n", out)
+
+			_, err = run("down", "2")
+			assert.Error(t, err)
+		}
+
+		t.Log("\ttest:4\tshould go to the version")
+		{
+			out, err := run("goto", "20200305183012")
+			assert.Nil(t, err)
+			assert.Equal(t, "up   20200305183012_card_answers\nversion 20200305183012\n", out)
+
+			_, err = run("goto", "20200101000000")
+			assert.Error(t, err)
+		}
+
+		t.Log("\ttest:5\tshould force the version")
+		{
+			out, err := run("force", "20200228130253")
+			assert.Nil(t, err)
+			assert.Equal(t, "forced version 20200228130253\n", out)
+
+			out, err = run("status")
+			assert.Nil(t, err)
+			assert.Contains(t, out, "pending  20200305183012_card_answers\n")
+		}
+
+		t.Log("\ttest:6\tshould create a migration")
+		{
+			dir := t.TempDir()
+
+			out, err := run("-dir", dir, "create", "users")
+			assert.Nil(t, err)
+			assert.Contains(t, out, "_users.up.sql\n")
+
+			files, err := filepath.Glob(filepath.Join(dir, "*_users.*.sql"))
+			assert.Nil(t, err)
+			assert.Len(t, files, 2)
+		}
+
+		t.Log("\ttest:7\tshould reject invalid usage")
+		{
+			for _, args := range [][]string{
+				{},
+				{"sideways"},
+				{"down"},
+				{"down", "zero"},
+				{"goto"},
+				{"force", "-2"},
+				{"create"},
+				{"create", "users"},
+				{"-unknown", "up"},
+			} {
+				_, err := run(args...)
+				assert.True(t, errors.Is(err, errUsage), "args %q: %v", args, err)
+			}
+		}
+	}
+}
+
+func TestRunCreateMigration(t *testing.T) {
+	t.Log("without config and database")
+	{
+		run := func(args ...string) (int, bool, string) {
+			var stdout, stderr bytes.Buffer
+			code, ok := runCreateMigration(args, &stdout, &stderr)
+			return code, ok, stdout.String() + stderr.String()
+		}
+
+		t.Log("\ttest:0\tshould create a migration")
+		{
+			dir := t.TempDir()
+
+			code, ok, out := run("-dir", dir, "create", "users")
+			assert.True(t, ok)
+			assert.Equal(t, exitOK, code)
+			assert.Contains(t, out, "_users.down.sql\n")
+
+			files, err := filepath.Glob(filepath.Join(dir, "*_users.*.sql"))
+			assert.Nil(t, err)
+			assert.Len(t, files, 2)
+		}
+
+		t.Log("\ttest:1\tshould report usage of create")
+		{
+			code, ok, out := run("create", "users")
+			assert.True(t, ok)
+			assert.Equal(t, exitError, code)
+			assert.Contains(t, out, "-dir is required")
+		}
+
+		t.Log("\ttest:2\tshould leave other commands")
+		{
+			for _, args := range [][]string{
+				{"up"},
+				{"-dry-run", "status"},
+				{},
+				{"-unknown", "create"},
+			} {
+				_, ok, _ := run(args...)
+				assert.False(t, ok, "args %q", args)
+			}
+		}
+	}
+}
@@ -0,0 +1,133 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	httpBroker "github.com/dipress/cards/internal/broker/http"
+)
+
+type closer struct {
+	closed int32
+}
+
+func (c *closer) Close() error {
+	atomic.StoreInt32(&c.closed, 1)
+	return nil
+}
+
+func (c *closer) isClosed() bool {
+	return atomic.LoadInt32(&c.closed) == 1
+}
+
+func startServer(t *testing.T, handler http.HandlerFunc) (*http.Server, context.CancelFunc) {
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to listen: %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+
+	srv := http.Server{
+		Addr:    lis.Addr().String(),
+		Handler: handler,
+		BaseContext: func(net.Listener) context.Context {
+			return ctx
+		},
+	}
+	go srv.Serve(lis)
+
+	return &srv, cancel
+}
+
+func TestShutdown(t *testing.T) {
+	t.Log("with a request in flight")
+	{
+		t.Log("\ttest:0\tshould wait for the request and close the db.")
+		{
+			started := make(chan struct{})
+			srv, cancelRequests := startServer(t, func(w http.ResponseWriter, r *http.Request) {
+				close(started)
+				time.Sleep(100 * time.Millisecond)
+				w.WriteHeader(http.StatusOK)
+			})
+
+			var readiness httpBroker.Readiness
+			readiness.SetReady(true)
+
+			codes := make(chan int, 1)
+			go func() {
+				resp, err := http.Get(fmt.Sprintf("http://%s", srv.Addr))
+				if err != nil {
+					codes <- 0
+					return
+				}
+				resp.Body.Close()
+				codes <- resp.StatusCode
+			}()
+			<-started
+
+			var db closer
+			if err := shutdown(srv, &readiness, cancelRequests, &db, 10*time.Millisecond, time.Second); err != nil {
+				t.Errorf("unexpected error: %v", err)
+			}
+
+			if code := <-codes; code != http.StatusOK {
+				t.Errorf("unexpected status code: %d expected: %d", code, http.StatusOK)
+			}
+
+			if readiness.Ready() {
+				t.Error("expected server to be marked as not ready")
+			}
+
+			if !db.isClosed() {
+				t.Error("expected db to be closed")
+			}
+		}
+
+		t.Log("\ttest:1\tshould cancel the request outliving the timeout.")
+		{
+			started := make(chan struct{})
+			cancelled := make(chan struct{})
+			srv, cancelRequests := startServer(t, func(w http.ResponseWriter, r *http.Request) {
+				close(started)
+				select {
+				case <-r.Context().Done():
+					close(cancelled)
+				case <-time.After(5 * time.Second):
+				}
+			})
+
+			var readiness httpBroker.Readiness
+			readiness.SetReady(true)
+
+			go func() {
+				resp, err := http.Get(fmt.Sprintf("http://%s", srv.Addr))
+				if err == nil {
+					resp.Body.Close()
+				}
+			}()
+			<-started
+
+			var db closer
+			if err := shutdown(srv, &readiness, cancelRequests, &db, 0, 50*time.Millisecond); err == nil {
+				t.Error("expected shutdown timeout error")
+			}
+
+			select {
+			case <-cancelled:
+			case <-time.After(time.Second):
+				t.Error("expected request context to be cancelled")
+			}
+
+			if !db.isClosed() {
+				t.Error("expected db to be closed")
+			}
+		}
+	}
+}
@@ -0,0 +1,159 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/dipress/cards/internal/card"
+	"github.com/dipress/cards/internal/storage/migration"
+	"github.com/dipress/cards/internal/storage/postgres"
+	pgSchema "github.com/dipress/cards/internal/storage/postgres/schema"
+	"github.com/dipress/cards/internal/storage/sqlite"
+	sqliteSchema "github.com/dipress/cards/internal/storage/sqlite/schema"
+)
+
+// sqliteScheme selects SQLite, the rest of the DSN is the database file path.
+const sqliteScheme = "sqlite://"
+
+// storage is the database backend picked by the DSN.
+type storage struct {
+	name     string
+	db       *sql.DB
+	replicas []*sql.DB
+	cards    card.Repository
+
+	// transactor is nil when the backend has no transactions.
+	transactor card.Transactor
+
+	schema migration.Migrator
+
+	migrate func() error
+	check   func(ctx context.Context) error
+	// prepare is nil when the backend doesn't cache statements.
+	prepare func(ctx context.Context) error
+	// checkReplicas is nil when there are no replicas.
+	checkReplicas func(ctx context.Context) error
+	// requestContext is nil when requests need no storage state.
+	requestContext func(ctx context.Context) context.Context
+}
+
+func openStorage(dsn string, replicaDSNs []string) (*storage, error) {
+	if strings.HasPrefix(dsn, sqliteScheme) {
+		if len(replicaDSNs) > 0 {
+			return nil, errors.New("sqlite doesn't support replicas")
+		}
+
+		return openSQLite(strings.TrimPrefix(dsn, sqliteScheme))
+	}
+
+	return openPostgres(dsn, replicaDSNs)
+}
+
+// Close closes the repository and then the primary and the replica databases.
+func (s *storage) Close() error {
+	var errs []string
+
+	if c, ok := s.cards.(io.Closer); ok {
+		if err := c.Close(); err != nil {
+			errs = append(errs, err.Error())
+		}
+	}
+
+	for _, db := range append([]*sql.DB{s.db}, s.replicas...) {
+		if err := db.Close(); err != nil {
+			errs = append(errs, err.Error())
+		}
+	}
+
+	if len(errs) > 0 {
+		return errors.New(strings.Join(errs, "; "))
+	}
+
+	return nil
+}
+
+func openPostgres(dsn string, replicaDSNs []string) (*storage, error) {
+	db, err := sql.Open("postgres", dsn)
+	if err != nil {
+		return nil, fmt.Errorf("open postgres: %w", err)
+	}
+
+	replicas := make([]*sql.DB, 0, len(replicaDSNs))
+	for i, replicaDSN := range replicaDSNs {
+		replica, err := sql.Open("postgres", replicaDSN)
+		if err != nil {
+			for _, r := range append(replicas, db) {
+				r.Close()
+			}
+			return nil, fmt.Errorf("open postgres replica %d: %w", i+1, err)
+		}
+
+		replicas = append(replicas, replica)
+	}
+
+	cards := postgres.NewCardRepository(db, replicas...)
+	schema := pgSchema.NewMigrator(db)
+
+	s := storage{
+		name:       "postgres",
+		db:         db,
+		replicas:   replicas,
+		cards:      cards,
+		transactor: postgres.NewTransactor(db),
+		schema:     schema,
+		migrate:    upMigrate(schema),
+		check: func(ctx context.Context) error {
+			return pgSchema.Check(ctx, db)
+		},
+		prepare: cards.Prepare,
+	}
+
+	if len(replicas) > 0 {
+		s.checkReplicas = cards.CheckReplicas
+		s.requestContext = postgres.WithReadYourWrites
+	}
+
+	return &s, nil
+}
+
+func openSQLite(path string) (*storage, error) {
+	if path == "" {
+		return nil, errors.New("sqlite database path is empty")
+	}
+
+	db, err := sqlite.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("open sqlite: %w", err)
+	}
+
+	schema := sqliteSchema.NewMigrator(db)
+
+	s := storage{
+		name:       "sqlite",
+		db:         db,
+		cards:      sqlite.NewCardRepository(db),
+		transactor: sqlite.NewTransactor(db),
+		schema:     schema,
+		migrate:    upMigrate(schema),
+		check: func(ctx context.Context) error {
+			return sqliteSchema.Check(ctx, db)
+		},
+	}
+
+	return &s, nil
+}
+
+// upMigrate applies pending migrations, a schema
+// at the latest version isn't an error.
+func upMigrate(m migration.Migrator) func() error {
+	return func() error {
+		if err := m.Up(); err != nil && !errors.Is(err, migration.ErrNoChange) {
+			return err
+		}
+		return nil
+	}
+}
@@ -0,0 +1,113 @@
+package main
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/dipress/cards/internal/card"
+)
+
+func TestOpenSQLiteStorage(t *testing.T) {
+	t.Log("with sqlite dsn")
+	{
+		store, err := openStorage(sqliteScheme+filepath.Join(t.TempDir(), "cards.db"), nil)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		defer store.db.Close()
+
+		ctx, cancel := context.WithTimeout(context.Background(), caseTimeout)
+		defer cancel()
+
+		t.Log("\ttest:0\tshould pick sqlite")
+		{
+			if store.name != "sqlite" {
+				t.Errorf("unexpected storage: %s expected: %s", store.name, "sqlite")
+			}
+		}
+
+		t.Log("\ttest:1\tshould migrate schema twice")
+		{
+			if err := store.migrate(); err != nil {
+				t.Errorf("unexpected error: %v", err)
+			}
+
+			if err := store.migrate(); err != nil {
+				t.Errorf("unexpected error: %v", err)
+			}
+
+			if err := store.check(ctx); err != nil {
+				t.Errorf("unexpected error: %v", err)
+			}
+		}
+
+		t.Log("\ttest:2\tshould store cards")
+		{
+			nc := card.NewCard{
+				UserID:        1,
+				Word:          "exceed",
+				Transcription: "ikˈsēd",
+				Translation:   "превышать",
+			}
+
+			var cd card.Card
+			if err := store.cards.Create(ctx, &nc, &cd); err != nil {
+				t.Errorf("unexpected error: %v", err)
+			}
+		}
+	}
+
+	t.Log("with sqlite dsn without path")
+	{
+		t.Log("\ttest:0\tshould get an error")
+		{
+			if _, err := openStorage(sqliteScheme, nil); err == nil {
+				t.Error("expected error")
+			}
+		}
+	}
+
+	t.Log("with sqlite dsn and replicas")
+	{
+		t.Log("\ttest:0\tshould get an error")
+		{
+			if _, err := openStorage(sqliteScheme+"cards.db", []string{"postgres://replica/cards"}); err == nil {
+				t.Error("expected error")
+			}
+		}
+	}
+}
+
+func TestOpenPostgresStorage(t *testing.T) {
+	t.Log("with postgres dsn and replicas")
+	{
+		store, err := openStorage("postgres://localhost/cards", []string{
+			"postgres://replica1/cards",
+			"postgres://replica2/cards",
+		})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		t.Log("\ttest:0\tshould open the replicas")
+		{
+			if store.name != "postgres" || len(store.replicas) != 2 || store.checkReplicas == nil {
+				t.Errorf("unexpected storage: %+v", store)
+			}
+		}
+
+		t.Log("\ttest:1\tshould close every database")
+		{
+			if err := store.Close(); err != nil {
+				t.Errorf("unexpected error: %v", err)
+			}
+
+			for _, db := range append(store.replicas, store.db) {
+				if err := db.Ping(); err == nil || err.Error() != "sql: database is closed" {
+					t.Errorf("unexpected error: %v", err)
+				}
+			}
+		}
+	}
+}
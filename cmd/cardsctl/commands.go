@@ -0,0 +1,240 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"strconv"
+
+	"github.com/dipress/cards/internal/card"
+	"github.com/dipress/cards/internal/client"
+)
+
+func newFlagSet(name string) *flag.FlagSet {
+	fs := flag.NewFlagSet(name, flag.ContinueOnError)
+	fs.SetOutput(ioutil.Discard)
+
+	return fs
+}
+
+func formFlags(fs *flag.FlagSet, f *card.Form) {
+	fs.IntVar(&f.UserID, "user", f.UserID, "user id")
+	fs.StringVar(&f.Word, "word", f.Word, "word")
+	fs.StringVar(&f.Transcription, "transcription", f.Transcription, "transcription")
+	fs.StringVar(&f.Translation, "translation", f.Translation, "translation")
+}
+
+func parseIDs(args []string) ([]int, error) {
+	if len(args) == 0 {
+		return nil, fmt.Errorf("card id is required: %w", errUsage)
+	}
+
+	ids := make([]int, 0, len(args))
+	for _, arg := range args {
+		id, err := strconv.Atoi(arg)
+		if err != nil {
+			return nil, fmt.Errorf("invalid card id %q: %w", arg, errUsage)
+		}
+		ids = append(ids, id)
+	}
+
+	return ids, nil
+}
+
+func addCommand(ctx context.Context, c *client.Client, cfg *config, args []string, in io.Reader, w io.Writer) error {
+	var f card.Form
+
+	fs := newFlagSet("add")
+	formFlags(fs, &f)
+	if err := fs.Parse(args); err != nil {
+		return fmt.Errorf("parse flags: %v: %w", err, errUsage)
+	}
+
+	cd, err := c.Create(ctx, &f)
+	if err != nil {
+		return err
+	}
+
+	return printCards(w, cfg.output, []card.Card{*cd})
+}
+
+func getCommand(ctx context.Context, c *client.Client, cfg *config, args []string, in io.Reader, w io.Writer) error {
+	ids, err := parseIDs(args)
+	if err != nil {
+		return err
+	}
+
+	cards := make([]card.Card, 0, len(ids))
+	for _, id := range ids {
+		cd, err := c.Find(ctx, id)
+		if err != nil {
+			return fmt.Errorf("card %d: %w", id, err)
+		}
+		cards = append(cards, *cd)
+	}
+
+	return printCards(w, cfg.output, cards)
+}
+
+func editCommand(ctx context.Context, c *client.Client, cfg *config, args []string, in io.Reader, w io.Writer) error {
+	if len(args) == 0 {
+		return fmt.Errorf("card id is required: %w", errUsage)
+	}
+
+	ids, err := parseIDs(args[:1])
+	if err != nil {
+		return err
+	}
+	id := ids[0]
+
+	var changes card.Form
+
+	fs := newFlagSet("edit")
+	formFlags(fs, &changes)
+	if err := fs.Parse(args[1:]); err != nil {
+		return fmt.Errorf("parse flags: %v: %w", err, errUsage)
+	}
+
+	if fs.NFlag() == 0 {
+		return fmt.Errorf("nothing to edit: %w", errUsage)
+	}
+
+	cd, err := c.Find(ctx, id)
+	if err != nil {
+		return fmt.Errorf("card %d: %w", id, err)
+	}
+
+	f := card.Form{
+		UserID:        cd.UserID,
+		Word:          cd.Word,
+		Transcription: cd.Transcription,
+		Translation:   cd.Translation,
+	}
+
+	// Apply only the flags which were set explicitly.
+	fs.Visit(func(fl *flag.Flag) {
+		switch fl.Name {
+		case "user":
+			f.UserID = changes.UserID
+		case "word":
+			f.Word = changes.Word
+		case "transcription":
+			f.Transcription = changes.Transcription
+		case "translation":
+			f.Translation = changes.Translation
+		}
+	})
+
+	cd, err = c.Update(ctx, id, &f)
+	if err != nil {
+		return fmt.Errorf("card %d: %w", id, err)
+	}
+
+	return printCards(w, cfg.output, []card.Card{*cd})
+}
+
+func rmCommand(ctx context.Context, c *client.Client, cfg *config, args []string, in io.Reader, w io.Writer) error {
+	ids, err := parseIDs(args)
+	if err != nil {
+		return err
+	}
+
+	for _, id := range ids {
+		if err := c.Delete(ctx, id); err != nil {
+			return fmt.Errorf("card %d: %w", id, err)
+		}
+		fmt.Fprintf(w, "card %d deleted\n", id)
+	}
+
+	return nil
+}
+
+func importCommand(ctx context.Context, c *client.Client, cfg *config, args []string, in io.Reader, w io.Writer) error {
+	var userID int
+
+	fs := newFlagSet("import")
+	fs.IntVar(&userID, "user", 0, "user id for rows without user_id column")
+	if err := fs.Parse(args); err != nil {
+		return fmt.Errorf("parse flags: %v: %w", err, errUsage)
+	}
+
+	if fs.NArg() != 1 {
+		return fmt.Errorf("csv file is required: %w", errUsage)
+	}
+
+	file, err := os.Open(fs.Arg(0))
+	if err != nil {
+		return fmt.Errorf("open file: %w", err)
+	}
+	defer file.Close()
+
+	forms, err := readCSV(file, userID)
+	if err != nil {
+		return fmt.Errorf("read csv: %w", err)
+	}
+
+	if len(forms) > card.MaxImport {
+		return fmt.Errorf("csv file has %d rows, at most %d are imported at once", len(forms), card.MaxImport)
+	}
+
+	cs, err := c.Import(ctx, forms)
+	if err != nil {
+		return err
+	}
+
+	return printCards(w, cfg.output, cs.Cards)
+}
+
+func exportCommand(ctx context.Context, c *client.Client, cfg *config, args []string, in io.Reader, w io.Writer) error {
+	var (
+		userID int
+		path   string
+	)
+
+	fs := newFlagSet("export")
+	fs.IntVar(&userID, "user", 0, "user id")
+	fs.StringVar(&path, "file", "", "output file, stdout by default")
+	if err := fs.Parse(args); err != nil {
+		return fmt.Errorf("parse flags: %v: %w", err, errUsage)
+	}
+
+	if userID == 0 {
+		return fmt.Errorf("user id is required: %w", errUsage)
+	}
+
+	cs, err := c.List(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	if path == "" {
+		return export(w, cfg.output, cs.Cards)
+	}
+
+	file, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("create file: %w", err)
+	}
+
+	if err := export(file, cfg.output, cs.Cards); err != nil {
+		file.Close()
+		return err
+	}
+
+	if err := file.Close(); err != nil {
+		return fmt.Errorf("close file: %w", err)
+	}
+
+	return nil
+}
+
+func export(w io.Writer, format string, cards []card.Card) error {
+	if format == formatJSON {
+		return printCards(w, format, cards)
+	}
+
+	return writeCSV(w, cards)
+}
@@ -0,0 +1,142 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"os/signal"
+	"sort"
+	"time"
+
+	"github.com/dipress/cards/internal/client"
+)
+
+const usage = `Usage: cardsctl [flags] <command> [arguments]
+
+Commands:
+  add     -user ID -word W -transcription T -translation T
+  get     ID [ID...]
+  edit    ID [-user ID] [-word W] [-transcription T] [-translation T]
+  rm      ID [ID...]
+  import  [-user ID] file.csv
+  export  -user ID [-file path]
+  study   -user ID [-limit N] [-shuffle]
+
+Flags:
+`
+
+// errUsage raises when command line arguments are invalid.
+var errUsage = errors.New("invalid usage")
+
+// config holds cardsctl settings,
+// flags take precedence over environment variables.
+type config struct {
+	url     string
+	output  string
+	timeout time.Duration
+}
+
+type command func(ctx context.Context, c *client.Client, cfg *config, args []string, in io.Reader, w io.Writer) error
+
+var commands = map[string]command{
+	"add":    addCommand,
+	"get":    getCommand,
+	"edit":   editCommand,
+	"rm":     rmCommand,
+	"import": importCommand,
+	"export": exportCommand,
+	"study":  studyCommand,
+}
+
+func main() {
+	if err := run(os.Args[1:], os.Stdin, os.Stdout, os.Stderr); err != nil {
+		fmt.Fprintf(os.Stderr, "cardsctl: %v\n", err)
+
+		// Usage errors exit with 2 like the flag package does.
+		if errors.Is(err, errUsage) {
+			os.Exit(2)
+		}
+		os.Exit(1)
+	}
+}
+
+func run(args []string, stdin io.Reader, stdout, stderr io.Writer) error {
+	var cfg config
+
+	fs := flag.NewFlagSet("cardsctl", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	fs.Usage = func() {
+		fmt.Fprint(stderr, usage)
+		fs.PrintDefaults()
+	}
+
+	fs.StringVar(&cfg.url, "url", env("CARDS_URL", "http://localhost:8080"), "cards server url (CARDS_URL)")
+	fs.StringVar(&cfg.output, "o", env("CARDS_OUTPUT", formatTable), "output format: table or json (CARDS_OUTPUT)")
+	fs.DurationVar(&cfg.timeout, "timeout", 15*time.Second, "timeout of a single request")
+
+	if err := fs.Parse(args); err != nil {
+		return errUsage
+	}
+
+	if cfg.output != formatTable && cfg.output != formatJSON {
+		return fmt.Errorf("unknown output format %q", cfg.output)
+	}
+
+	if fs.NArg() == 0 {
+		fs.Usage()
+		return errUsage
+	}
+
+	cmd, ok := commands[fs.Arg(0)]
+	if !ok {
+		fmt.Fprintf(stderr, "unknown command %q, available: %v\n", fs.Arg(0), commandNames())
+		return errUsage
+	}
+
+	c, err := client.New(cfg.url, client.WithHTTPClient(&http.Client{
+		Timeout: cfg.timeout,
+	}))
+	if err != nil {
+		return fmt.Errorf("new client: %w", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// Cancel in-flight requests on interrupt.
+	osSignals := make(chan os.Signal, 1)
+	signal.Notify(osSignals, os.Interrupt)
+	defer signal.Stop(osSignals)
+
+	go func() {
+		select {
+		case <-osSignals:
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+
+	return cmd(ctx, c, &cfg, fs.Args()[1:], stdin, stdout)
+}
+
+func env(key, fallback string) string {
+	if v, ok := os.LookupEnv(key); ok {
+		return v
+	}
+
+	return fallback
+}
+
+func commandNames() []string {
+	names := make([]string, 0, len(commands))
+	for name := range commands {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	return names
+}
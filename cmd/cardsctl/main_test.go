@@ -0,0 +1,166 @@
+package main
+
+import (
+	"bytes"
+	"io/ioutil"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	httpBroker "github.com/dipress/cards/internal/broker/http"
+	"github.com/dipress/cards/internal/card"
+	"github.com/dipress/cards/internal/kit/logger"
+	"github.com/dipress/cards/internal/validation"
+	gomock "github.com/golang/mock/gomock"
+)
+
+func TestRun(t *testing.T) {
+	dir, err := ioutil.TempDir("", "cardsctl")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer os.RemoveAll(dir)
+
+	importFile := filepath.Join(dir, "import.csv")
+	if err := ioutil.WriteFile(importFile, []byte("word,transcription,translation\ndo,do͞o,делать\n"), 0644); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	tests := []struct {
+		name           string
+		args           []string
+		stdin          string
+		repositoryFunc func(m *card.MockRepository)
+		contains       string
+		wantErr        bool
+	}{
+		{
+			name: "add",
+			args: []string{"add", "-user", "1", "-word", "do", "-transcription", "do͞o", "-translation", "делать"},
+			repositoryFunc: func(m *card.MockRepository) {
+				m.EXPECT().Create(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil)
+			},
+			contains: "TRANSLATION",
+		},
+		{
+			name: "get as json",
+			args: []string{"-o", "json", "get", "7"},
+			repositoryFunc: func(m *card.MockRepository) {
+				m.EXPECT().Find(gomock.Any(), 7).Return(&card.Card{ID: 7, Word: "own"}, nil)
+			},
+			contains: `"word": "own"`,
+		},
+		{
+			name: "get not found",
+			args: []string{"get", "7"},
+			repositoryFunc: func(m *card.MockRepository) {
+				m.EXPECT().Find(gomock.Any(), 7).Return(nil, card.ErrNotFound)
+			},
+			wantErr: true,
+		},
+		{
+			name: "edit",
+			args: []string{"edit", "7", "-translation", "обладать"},
+			repositoryFunc: func(m *card.MockRepository) {
+				cd := card.Card{ID: 7, Word: "own", Transcription: "ōn", Translation: "владеть"}
+				m.EXPECT().Find(gomock.Any(), 7).Return(&cd, nil)
+				m.EXPECT().Update(gomock.Any(), 7, gomock.Any()).Return(nil)
+			},
+			contains: "обладать",
+		},
+		{
+			name:           "edit without changes",
+			args:           []string{"edit", "7"},
+			repositoryFunc: func(m *card.MockRepository) {},
+			wantErr:        true,
+		},
+		{
+			name: "rm",
+			args: []string{"rm", "7"},
+			repositoryFunc: func(m *card.MockRepository) {
+				m.EXPECT().Delete(gomock.Any(), 7).Return(nil)
+			},
+			contains: "card 7 deleted",
+		},
+		{
+			name: "import",
+			args: []string{"import", "-user", "1", importFile},
+			repositoryFunc: func(m *card.MockRepository) {
+				m.EXPECT().Create(gomock.Any(), &card.NewCard{
+					UserID:        1,
+					Word:          "do",
+					Transcription: "do͞o",
+					Translation:   "делать",
+				}, gomock.Any()).Return(nil)
+			},
+			contains: "TRANSLATION",
+		},
+		{
+			name: "export",
+			args: []string{"export", "-user", "1"},
+			repositoryFunc: func(m *card.MockRepository) {
+				m.EXPECT().List(gomock.Any(), 1).Return([]card.Card{{UserID: 1, Word: "do", Transcription: "do͞o", Translation: "делать"}}, nil)
+			},
+			contains: "do,do͞o,делать,1",
+		},
+		{
+			name:  "study",
+			args:  []string{"study", "-user", "1"},
+			stdin: "\ny\n\nn\n",
+			repositoryFunc: func(m *card.MockRepository) {
+				m.EXPECT().List(gomock.Any(), 1).Return([]card.Card{{ID: 1, Word: "do"}, {ID: 2, Word: "own"}}, nil)
+				m.EXPECT().Answer(gomock.Any(), 1, true).Return(&card.Card{ID: 1}, nil)
+				m.EXPECT().Answer(gomock.Any(), 2, false).Return(&card.Card{ID: 2}, nil)
+			},
+			contains: "correct: 1, wrong: 1, skipped: 0",
+		},
+		{
+			name:           "unknown command",
+			args:           []string{"fly"},
+			repositoryFunc: func(m *card.MockRepository) {},
+			wantErr:        true,
+		},
+	}
+
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			repo := card.NewMockRepository(ctrl)
+			tc.repositoryFunc(repo)
+
+			l, err := logger.New(logger.SetOutput(ioutil.Discard))
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			services := httpBroker.Services{
+				Card: card.NewService(repo, &validation.Card{}),
+			}
+
+			srv := httptest.NewServer(httpBroker.NewServer("", l, &services).Handler)
+			defer srv.Close()
+
+			var stdout, stderr bytes.Buffer
+			err = run(append([]string{"-url", srv.URL}, tc.args...), strings.NewReader(tc.stdin), &stdout, &stderr)
+			if tc.wantErr {
+				if err == nil {
+					t.Error("expected error")
+				}
+				return
+			}
+
+			if err != nil {
+				t.Errorf("unexpected error: %v", err)
+			}
+
+			if !strings.Contains(stdout.String(), tc.contains) {
+				t.Errorf("unexpected output:\n\t\t%s\nexpected to contain:\n\t\t%s", stdout.String(), tc.contains)
+			}
+		})
+	}
+}
@@ -0,0 +1,145 @@
+package main
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/dipress/cards/internal/card"
+)
+
+const (
+	formatTable = "table"
+	formatJSON  = "json"
+)
+
+var csvHeader = []string{"word", "transcription", "translation", "user_id"}
+
+func printCards(w io.Writer, format string, cards []card.Card) error {
+	if format == formatJSON {
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(card.Cards{Cards: cards}); err != nil {
+			return fmt.Errorf("encode: %w", err)
+		}
+
+		return nil
+	}
+
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "ID\tUSER\tWORD\tTRANSCRIPTION\tTRANSLATION\tUPDATED")
+	for _, cd := range cards {
+		fmt.Fprintf(tw, "%d\t%d\t%s\t%s\t%s\t%s\n",
+			cd.ID,
+			cd.UserID,
+			cd.Word,
+			cd.Transcription,
+			cd.Translation,
+			cd.UpdatedAt.Format(time.RFC3339),
+		)
+	}
+
+	if err := tw.Flush(); err != nil {
+		return fmt.Errorf("flush: %w", err)
+	}
+
+	return nil
+}
+
+// readCSV reads card forms from csv with a header line,
+// userID is used for rows without user_id column.
+func readCSV(r io.Reader, userID int) ([]card.Form, error) {
+	cr := csv.NewReader(r)
+	cr.FieldsPerRecord = -1
+	cr.TrimLeadingSpace = true
+
+	header, err := cr.Read()
+	if err != nil {
+		if errors.Is(err, io.EOF) {
+			return nil, errors.New("header is missing")
+		}
+		return nil, fmt.Errorf("read header: %w", err)
+	}
+
+	columns := make(map[string]int, len(header))
+	for i, name := range header {
+		columns[strings.ToLower(strings.TrimSpace(name))] = i
+	}
+
+	for _, name := range csvHeader[:3] {
+		if _, ok := columns[name]; !ok {
+			return nil, fmt.Errorf("column %q is missing", name)
+		}
+	}
+
+	value := func(record []string, name string) string {
+		i, ok := columns[name]
+		if !ok || i >= len(record) {
+			return ""
+		}
+		return strings.TrimSpace(record[i])
+	}
+
+	forms := make([]card.Form, 0)
+	for row := 1; ; row++ {
+		record, err := cr.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("read record: %w", err)
+		}
+
+		f := card.Form{
+			UserID:        userID,
+			Word:          value(record, "word"),
+			Transcription: value(record, "transcription"),
+			Translation:   value(record, "translation"),
+		}
+
+		if v := value(record, "user_id"); v != "" {
+			id, err := strconv.Atoi(v)
+			if err != nil {
+				return nil, fmt.Errorf("row %d: invalid user_id %q", row, v)
+			}
+			f.UserID = id
+		}
+
+		forms = append(forms, f)
+	}
+
+	return forms, nil
+}
+
+// writeCSV writes cards in the format readCSV understands.
+func writeCSV(w io.Writer, cards []card.Card) error {
+	cw := csv.NewWriter(w)
+
+	if err := cw.Write(csvHeader); err != nil {
+		return fmt.Errorf("write header: %w", err)
+	}
+
+	for _, cd := range cards {
+		if err := cw.Write([]string{
+			cd.Word,
+			cd.Transcription,
+			cd.Translation,
+			strconv.Itoa(cd.UserID),
+		}); err != nil {
+			return fmt.Errorf("write record: %w", err)
+		}
+	}
+
+	cw.Flush()
+	if err := cw.Error(); err != nil {
+		return fmt.Errorf("flush: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,90 @@
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/dipress/cards/internal/card"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReadCSV(t *testing.T) {
+	tests := []struct {
+		name    string
+		csv     string
+		userID  int
+		expect  []card.Form
+		wantErr bool
+	}{
+		{
+			name: "ok",
+			csv: "word,transcription,translation,user_id\n" +
+				"do,do͞o,делать,2\n" +
+				"own,ōn,владеть,\n",
+			userID: 1,
+			expect: []card.Form{
+				{UserID: 2, Word: "do", Transcription: "do͞o", Translation: "делать"},
+				{UserID: 1, Word: "own", Transcription: "ōn", Translation: "владеть"},
+			},
+		},
+		{
+			name: "without user_id column",
+			csv: "translation, word, transcription\n" +
+				"делать, do, do͞o\n",
+			userID: 3,
+			expect: []card.Form{
+				{UserID: 3, Word: "do", Transcription: "do͞o", Translation: "делать"},
+			},
+		},
+		{
+			name:    "missing column",
+			csv:     "word,translation\ndo,делать\n",
+			wantErr: true,
+		},
+		{
+			name:    "invalid user_id",
+			csv:     "word,transcription,translation,user_id\ndo,do͞o,делать,one\n",
+			wantErr: true,
+		},
+		{
+			name:    "empty",
+			csv:     "",
+			wantErr: true,
+		},
+	}
+
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			forms, err := readCSV(strings.NewReader(tc.csv), tc.userID)
+			if tc.wantErr {
+				assert.Error(t, err)
+				return
+			}
+
+			assert.Nil(t, err)
+			assert.Equal(t, tc.expect, forms)
+		})
+	}
+}
+
+func TestWriteCSV(t *testing.T) {
+	t.Parallel()
+
+	cards := []card.Card{
+		{ID: 1, UserID: 2, Word: "do", Transcription: "do͞o", Translation: "делать, выполнять"},
+	}
+
+	var buf bytes.Buffer
+	err := writeCSV(&buf, cards)
+	assert.Nil(t, err)
+
+	forms, err := readCSV(&buf, 0)
+	assert.Nil(t, err)
+	assert.Equal(t, []card.Form{
+		{UserID: 2, Word: "do", Transcription: "do͞o", Translation: "делать, выполнять"},
+	}, forms)
+}
@@ -0,0 +1,282 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand"
+	"os"
+	"sort"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/dipress/cards/internal/card"
+	"github.com/dipress/cards/internal/client"
+	"golang.org/x/term"
+)
+
+const (
+	keyEnter = '\n'
+	keyQuit  = 'q'
+)
+
+// errQuit raises when the learner stops the session.
+var errQuit = errors.New("quit")
+
+func studyCommand(ctx context.Context, c *client.Client, cfg *config, args []string, in io.Reader, w io.Writer) error {
+	var (
+		userID  int
+		limit   int
+		shuffle bool
+	)
+
+	fs := newFlagSet("study")
+	fs.IntVar(&userID, "user", 0, "user id")
+	fs.IntVar(&limit, "limit", 0, "maximum number of cards, all by default")
+	fs.BoolVar(&shuffle, "shuffle", false, "shuffle cards instead of least recently answered first")
+	if err := fs.Parse(args); err != nil {
+		return fmt.Errorf("parse flags: %v: %w", err, errUsage)
+	}
+
+	if userID == 0 {
+		return fmt.Errorf("user id is required: %w", errUsage)
+	}
+
+	cs, err := c.List(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	cards := order(cs.Cards, shuffle)
+	if limit > 0 && limit < len(cards) {
+		cards = cards[:limit]
+	}
+
+	var keys keyReader = &lineReader{bufio.NewReader(in)}
+
+	// Read single key presses when attached to a terminal.
+	if f, ok := in.(*os.File); ok && term.IsTerminal(int(f.Fd())) {
+		state, err := term.MakeRaw(int(f.Fd()))
+		if err != nil {
+			return fmt.Errorf("make raw terminal: %w", err)
+		}
+		defer term.Restore(int(f.Fd()), state)
+
+		keys = &rawReader{bufio.NewReader(f)}
+		w = &crlfWriter{w}
+	}
+
+	s := study{
+		client: c,
+		keys:   keys,
+		w:      w,
+	}
+
+	res, err := s.run(ctx, cards)
+	fmt.Fprintf(w, "\ncorrect: %d, wrong: %d, skipped: %d\n", res.correct, res.wrong, res.skipped)
+
+	return err
+}
+
+// order puts cards which were never answered first
+// and then the least recently answered ones.
+func order(cards []card.Card, shuffle bool) []card.Card {
+	ordered := append([]card.Card(nil), cards...)
+
+	if shuffle {
+		r := rand.New(rand.NewSource(time.Now().UnixNano()))
+		r.Shuffle(len(ordered), func(i, j int) {
+			ordered[i], ordered[j] = ordered[j], ordered[i]
+		})
+
+		return ordered
+	}
+
+	sort.SliceStable(ordered, func(i, j int) bool {
+		a, b := ordered[i].AnsweredAt, ordered[j].AnsweredAt
+		switch {
+		case a == nil:
+			return b != nil
+		case b == nil:
+			return false
+		}
+
+		return a.Before(*b)
+	})
+
+	return ordered
+}
+
+type studyClient interface {
+	Answer(ctx context.Context, id int, correct bool) (*card.Card, error)
+}
+
+type studyResult struct {
+	correct int
+	wrong   int
+	skipped int
+}
+
+// study is an interactive session which shows
+// cards one by one and records self-graded answers.
+type study struct {
+	client studyClient
+	keys   keyReader
+	w      io.Writer
+}
+
+func (s *study) run(ctx context.Context, cards []card.Card) (studyResult, error) {
+	var res studyResult
+
+	if len(cards) == 0 {
+		fmt.Fprintln(s.w, "no cards to study")
+		return res, nil
+	}
+
+	for i, cd := range cards {
+		fmt.Fprintf(s.w, "\n[%d/%d] %s\n", i+1, len(cards), cd.Word)
+		fmt.Fprint(s.w, "press any key to reveal, q to quit ")
+
+		key, err := s.keys.ReadKey()
+		if err != nil {
+			return res, s.stop(err)
+		}
+		if key == keyQuit {
+			return res, nil
+		}
+
+		fmt.Fprintf(s.w, "\n%s  %s\n", cd.Transcription, cd.Translation)
+
+		correct, err := s.grade()
+		if err != nil {
+			return res, s.stop(err)
+		}
+
+		if correct == nil {
+			res.skipped++
+			continue
+		}
+
+		if _, err := s.client.Answer(ctx, cd.ID, *correct); err != nil {
+			return res, fmt.Errorf("card %d: %w", cd.ID, err)
+		}
+
+		if *correct {
+			res.correct++
+		} else {
+			res.wrong++
+		}
+	}
+
+	return res, nil
+}
+
+// grade asks the learner whether the answer was correct,
+// nil means the card is skipped.
+func (s *study) grade() (*bool, error) {
+	yes, no := true, false
+
+	for {
+		fmt.Fprint(s.w, "did you know it? [y]es [n]o [s]kip [q]uit ")
+
+		key, err := s.keys.ReadKey()
+		if err != nil {
+			return nil, err
+		}
+
+		switch key {
+		case 'y':
+			fmt.Fprintln(s.w)
+			return &yes, nil
+		case 'n':
+			fmt.Fprintln(s.w)
+			return &no, nil
+		case 's':
+			fmt.Fprintln(s.w)
+			return nil, nil
+		case keyQuit:
+			return nil, errQuit
+		}
+
+		fmt.Fprintln(s.w)
+	}
+}
+
+// stop treats quit and end of input as a normal end of the session.
+func (s *study) stop(err error) error {
+	if errors.Is(err, errQuit) || errors.Is(err, io.EOF) {
+		return nil
+	}
+
+	return fmt.Errorf("read key: %w", err)
+}
+
+// keyReader reads key presses in lower case,
+// Enter is reported as keyEnter.
+type keyReader interface {
+	ReadKey() (rune, error)
+}
+
+// lineReader reads the first key of every line,
+// it is used when input isn't a terminal.
+type lineReader struct {
+	r *bufio.Reader
+}
+
+// ReadKey implements keyReader interface.
+func (l *lineReader) ReadKey() (rune, error) {
+	line, err := l.r.ReadString('\n')
+	if err != nil && (line == "" || !errors.Is(err, io.EOF)) {
+		return 0, err
+	}
+
+	line = strings.TrimSpace(line)
+	if line == "" {
+		return keyEnter, nil
+	}
+
+	r, _ := utf8.DecodeRuneInString(line)
+
+	return unicode.ToLower(r), nil
+}
+
+// rawReader reads keys from a terminal in raw mode.
+type rawReader struct {
+	r *bufio.Reader
+}
+
+// ReadKey implements keyReader interface.
+func (rr *rawReader) ReadKey() (rune, error) {
+	r, _, err := rr.r.ReadRune()
+	if err != nil {
+		return 0, err
+	}
+
+	switch r {
+	case '\r':
+		return keyEnter, nil
+	case 3, 4: // Ctrl-C and Ctrl-D.
+		return keyQuit, nil
+	}
+
+	return unicode.ToLower(r), nil
+}
+
+// crlfWriter translates line feeds for a terminal in raw mode.
+type crlfWriter struct {
+	w io.Writer
+}
+
+// Write implements io.Writer interface.
+func (c *crlfWriter) Write(p []byte) (int, error) {
+	if _, err := c.w.Write(bytes.Replace(p, []byte("\n"), []byte("\r\n"), -1)); err != nil {
+		return 0, err
+	}
+
+	return len(p), nil
+}
@@ -0,0 +1,120 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/dipress/cards/internal/card"
+	"github.com/stretchr/testify/assert"
+)
+
+type answer struct {
+	id      int
+	correct bool
+}
+
+type fakeStudyClient struct {
+	answers []answer
+}
+
+func (f *fakeStudyClient) Answer(ctx context.Context, id int, correct bool) (*card.Card, error) {
+	f.answers = append(f.answers, answer{id, correct})
+	return &card.Card{ID: id}, nil
+}
+
+func TestStudy(t *testing.T) {
+	cards := []card.Card{
+		{ID: 1, Word: "do", Transcription: "do͞o", Translation: "делать"},
+		{ID: 2, Word: "own", Transcription: "ōn", Translation: "владеть"},
+		{ID: 3, Word: "grow", Transcription: "grō", Translation: "расти"},
+	}
+
+	tests := []struct {
+		name    string
+		input   string
+		answers []answer
+		expect  studyResult
+	}{
+		{
+			name:    "all answered",
+			input:   "\ny\n\nn\n\nYes\n",
+			answers: []answer{{1, true}, {2, false}, {3, true}},
+			expect:  studyResult{correct: 2, wrong: 1},
+		},
+		{
+			name:    "skip and unknown keys",
+			input:   "\nx\ns\n\nn\n\ns\n",
+			answers: []answer{{2, false}},
+			expect:  studyResult{wrong: 1, skipped: 2},
+		},
+		{
+			name:    "quit on reveal",
+			input:   "\ny\nq\n",
+			answers: []answer{{1, true}},
+			expect:  studyResult{correct: 1},
+		},
+		{
+			name:    "quit on grade",
+			input:   "\nq\n",
+			answers: nil,
+			expect:  studyResult{},
+		},
+		{
+			name:    "end of input",
+			input:   "\ny",
+			answers: []answer{{1, true}},
+			expect:  studyResult{correct: 1},
+		},
+	}
+
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			var (
+				fake fakeStudyClient
+				out  bytes.Buffer
+			)
+
+			s := study{
+				client: &fake,
+				keys:   &lineReader{bufio.NewReader(strings.NewReader(tc.input))},
+				w:      &out,
+			}
+
+			res, err := s.run(context.Background(), cards)
+			assert.Nil(t, err)
+			assert.Equal(t, tc.expect, res)
+			assert.Equal(t, tc.answers, fake.answers)
+		})
+	}
+}
+
+func TestOrder(t *testing.T) {
+	t.Parallel()
+
+	now := time.Now()
+	earlier := now.Add(-time.Hour)
+
+	cards := []card.Card{
+		{ID: 1, AnsweredAt: &now},
+		{ID: 2},
+		{ID: 3, AnsweredAt: &earlier},
+		{ID: 4},
+	}
+
+	ordered := order(cards, false)
+
+	ids := make([]int, 0, len(ordered))
+	for _, cd := range ordered {
+		ids = append(ids, cd.ID)
+	}
+
+	assert.Equal(t, []int{2, 4, 3, 1}, ids)
+	assert.Equal(t, 1, cards[0].ID, "expected source slice to stay untouched")
+}
@@ -0,0 +1,49 @@
+package http
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/dipress/cards/internal/broker/http/handler"
+)
+
+// UserIDHeader holds id of the user who makes the request.
+const UserIDHeader = "X-User-ID"
+
+// accessLogMiddleware logs every request.
+func accessLogMiddleware(logger Logger) handler.Middleware {
+	return func(next handler.Handler) handler.Handler {
+		h := handler.Func(func(w http.ResponseWriter, r *http.Request) error {
+			start := time.Now()
+			sw := newStatusWriter(w)
+
+			err := next.Handle(sw, r)
+
+			logger.Info("request", requestFields(r, map[string]interface{}{
+				"route":       routeTemplate(r),
+				"status":      sw.Status(),
+				"bytes":       sw.bytes,
+				"duration":    time.Since(start).Seconds(),
+				"remote_addr": r.RemoteAddr,
+				"user_id":     userID(r),
+			}))
+
+			return err
+		})
+
+		return h
+	}
+}
+
+// userID takes id of the user from the header and falls back
+// to the user_id query parameter, 0 means it's unknown.
+func userID(r *http.Request) int {
+	for _, v := range []string{r.Header.Get(UserIDHeader), r.URL.Query().Get("user_id")} {
+		if id, err := strconv.Atoi(v); err == nil {
+			return id
+		}
+	}
+
+	return 0
+}
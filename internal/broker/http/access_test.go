@@ -0,0 +1,62 @@
+package http
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/dipress/cards/internal/broker/http/handler"
+	"github.com/golang/mock/gomock"
+	"github.com/gorilla/mux"
+)
+
+func Test_accessLogMiddleware(t *testing.T) {
+	t.Parallel()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	logger := NewMockLogger(ctrl)
+
+	next := handler.Func(func(w http.ResponseWriter, r *http.Request) error {
+		w.WriteHeader(http.StatusCreated)
+		w.Write([]byte(`{"id":1}`))
+		return nil
+	})
+
+	router := mux.NewRouter()
+	router.Handle("/cards/{id}", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		r = r.WithContext(context.WithValue(r.Context(), requestIDKey{}, "abc-123"))
+		accessLogMiddleware(logger)(next).Handle(w, r)
+	}))
+
+	req := httptest.NewRequest(http.MethodPut, "/cards/1", nil)
+	req.Header.Set(UserIDHeader, "7")
+	req.RemoteAddr = "192.0.2.1:1234"
+
+	logger.EXPECT().Info("request", gomock.Any()).Do(func(_ string, extra map[string]interface{}) {
+		expect := map[string]interface{}{
+			"method":      http.MethodPut,
+			"path":        "/cards/1",
+			"route":       "/cards/{id}",
+			"status":      http.StatusCreated,
+			"bytes":       8,
+			"remote_addr": "192.0.2.1:1234",
+			"user_id":     7,
+			"request_id":  "abc-123",
+		}
+
+		for k, v := range expect {
+			if extra[k] != v {
+				t.Errorf("unexpected %s: %v expected %v", k, extra[k], v)
+			}
+		}
+
+		if _, ok := extra["duration"].(float64); !ok {
+			t.Errorf("expected duration: %v", extra["duration"])
+		}
+	})
+
+	router.ServeHTTP(httptest.NewRecorder(), req)
+}
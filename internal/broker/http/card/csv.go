@@ -0,0 +1,53 @@
+package card
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/dipress/cards/internal/card"
+)
+
+// cardList writes cards as CSV in addition to
+// the formats card.Cards is encoded in.
+type cardList card.Cards
+
+var csvHeader = []string{
+	"id",
+	"user_id",
+	"word",
+	"transcription",
+	"translation",
+	"correct_answers",
+	"wrong_answers",
+	"answered_at",
+	"created_at",
+	"updated_at",
+}
+
+// MarshalCSV implements response.CSVMarshaler interface.
+func (l *cardList) MarshalCSV() ([][]string, error) {
+	records := make([][]string, 0, len(l.Cards)+1)
+	records = append(records, csvHeader)
+
+	for _, c := range l.Cards {
+		var answeredAt string
+		if c.AnsweredAt != nil {
+			answeredAt = c.AnsweredAt.Format(time.RFC3339)
+		}
+
+		records = append(records, []string{
+			strconv.Itoa(c.ID),
+			strconv.Itoa(c.UserID),
+			c.Word,
+			c.Transcription,
+			c.Translation,
+			strconv.Itoa(c.CorrectAnswers),
+			strconv.Itoa(c.WrongAnswers),
+			answeredAt,
+			c.CreatedAt.Format(time.RFC3339),
+			c.UpdatedAt.Format(time.RFC3339),
+		})
+	}
+
+	return records, nil
+}
@@ -2,7 +2,6 @@ package card
 
 import (
 	"context"
-	"encoding/json"
 	"fmt"
 	"net/http"
 	"strconv"
@@ -23,11 +22,20 @@ type Handler interface {
 // Service contains all services.
 type Service interface {
 	Create(ctx context.Context, f *card.Form) (*card.Card, error)
+	Import(ctx context.Context, forms []card.Form) (*card.Cards, error)
 	Find(ctx context.Context, id int) (*card.Card, error)
+	List(ctx context.Context, userID int) (*card.Cards, error)
 	Update(ctx context.Context, id int, f *card.Form) (*card.Card, error)
+	Answer(ctx context.Context, id int, f *card.AnswerForm) (*card.Card, error)
 	Delete(ctx context.Context, id int) error
 }
 
+// Offered response codecs, the first one is the default.
+var (
+	cardCodecs  = []response.Codec{response.JSON, response.MsgPack}
+	cardsCodecs = []response.Codec{response.JSON, response.MsgPack, response.CSV}
+)
+
 // CreateHandler for create requests.
 type CreateHandler struct {
 	Service
@@ -36,17 +44,22 @@ type CreateHandler struct {
 // Handle implements Handler interface.
 func (h *CreateHandler) Handle(w http.ResponseWriter, r *http.Request) error {
 	if err := h.process(w, r); err != nil {
-		return response.HandleError(err, w)
+		return response.HandleError(err, w, r)
 	}
 
 	return nil
 }
 
 func (h *CreateHandler) process(w http.ResponseWriter, r *http.Request) error {
+	codec, err := response.Negotiate(r, cardCodecs...)
+	if err != nil {
+		return err
+	}
+
 	var f card.Form
 
-	if err := json.NewDecoder(r.Body).Decode(&f); err != nil {
-		return response.ErrBadRequest
+	if err := response.Decode(r, &f); err != nil {
+		return err
 	}
 
 	card, err := h.Create(r.Context(), &f)
@@ -54,13 +67,47 @@ func (h *CreateHandler) process(w http.ResponseWriter, r *http.Request) error {
 		return fmt.Errorf("create: %w", err)
 	}
 
-	if err := json.NewEncoder(w).Encode(&card); err != nil {
-		return fmt.Errorf("encode: %w", err)
+	return response.Write(w, codec, http.StatusOK, card)
+}
+
+// ImportHandler for import requests, the cards are created all
+// together or not at all. A request takes up to card.MaxImport cards.
+type ImportHandler struct {
+	Service
+}
+
+// Handle implements Handler interface.
+func (h *ImportHandler) Handle(w http.ResponseWriter, r *http.Request) error {
+	if err := h.process(w, r); err != nil {
+		return response.HandleError(err, w, r)
 	}
 
 	return nil
 }
 
+func (h *ImportHandler) process(w http.ResponseWriter, r *http.Request) error {
+	codec, err := response.Negotiate(r, cardsCodecs...)
+	if err != nil {
+		return err
+	}
+
+	var f card.ImportForm
+	if err := response.Decode(r, &f); err != nil {
+		return err
+	}
+
+	if len(f.Cards) == 0 || len(f.Cards) > card.MaxImport {
+		return response.ErrBadRequest
+	}
+
+	cards, err := h.Import(r.Context(), f.Cards)
+	if err != nil {
+		return fmt.Errorf("import: %w", err)
+	}
+
+	return response.Write(w, codec, http.StatusOK, (*cardList)(cards))
+}
+
 // FindHandler for find requests.
 type FindHandler struct {
 	Service
@@ -68,13 +115,18 @@ type FindHandler struct {
 
 func (h *FindHandler) Handle(w http.ResponseWriter, r *http.Request) error {
 	if err := h.process(w, r); err != nil {
-		return response.HandleError(err, w)
+		return response.HandleError(err, w, r)
 	}
 
 	return nil
 }
 
 func (h *FindHandler) process(w http.ResponseWriter, r *http.Request) error {
+	codec, err := response.Negotiate(r, cardCodecs...)
+	if err != nil {
+		return err
+	}
+
 	vars := mux.Vars(r)
 
 	id, err := strconv.Atoi(vars["id"])
@@ -87,13 +139,41 @@ func (h *FindHandler) process(w http.ResponseWriter, r *http.Request) error {
 		return fmt.Errorf("find: %w", err)
 	}
 
-	if err := json.NewEncoder(w).Encode(&card); err != nil {
-		return fmt.Errorf("encode: %w", err)
+	return response.Write(w, codec, http.StatusOK, card)
+}
+
+// ListHandler for list requests.
+type ListHandler struct {
+	Service
+}
+
+func (h *ListHandler) Handle(w http.ResponseWriter, r *http.Request) error {
+	if err := h.process(w, r); err != nil {
+		return response.HandleError(err, w, r)
 	}
 
 	return nil
 }
 
+func (h *ListHandler) process(w http.ResponseWriter, r *http.Request) error {
+	codec, err := response.Negotiate(r, cardsCodecs...)
+	if err != nil {
+		return err
+	}
+
+	userID, err := strconv.Atoi(r.URL.Query().Get("user_id"))
+	if err != nil {
+		return response.ErrBadRequest
+	}
+
+	cards, err := h.Service.List(r.Context(), userID)
+	if err != nil {
+		return fmt.Errorf("list: %w", err)
+	}
+
+	return response.Write(w, codec, http.StatusOK, (*cardList)(cards))
+}
+
 // UpdateHandler for update requests.
 type UpdateHandler struct {
 	Service
@@ -101,13 +181,18 @@ type UpdateHandler struct {
 
 func (h *UpdateHandler) Handle(w http.ResponseWriter, r *http.Request) error {
 	if err := h.process(w, r); err != nil {
-		return response.HandleError(err, w)
+		return response.HandleError(err, w, r)
 	}
 
 	return nil
 }
 
 func (h *UpdateHandler) process(w http.ResponseWriter, r *http.Request) error {
+	codec, err := response.Negotiate(r, cardCodecs...)
+	if err != nil {
+		return err
+	}
+
 	vars := mux.Vars(r)
 
 	id, err := strconv.Atoi(vars["id"])
@@ -116,8 +201,8 @@ func (h *UpdateHandler) process(w http.ResponseWriter, r *http.Request) error {
 	}
 
 	var f card.Form
-	if err := json.NewDecoder(r.Body).Decode(&f); err != nil {
-		return response.ErrBadRequest
+	if err := response.Decode(r, &f); err != nil {
+		return err
 	}
 
 	card, err := h.Service.Update(r.Context(), id, &f)
@@ -125,13 +210,48 @@ func (h *UpdateHandler) process(w http.ResponseWriter, r *http.Request) error {
 		return fmt.Errorf("update: %w", err)
 	}
 
-	if err := json.NewEncoder(w).Encode(&card); err != nil {
-		return fmt.Errorf("encode: %w", err)
+	return response.Write(w, codec, http.StatusOK, card)
+}
+
+// AnswerHandler for answer requests.
+type AnswerHandler struct {
+	Service
+}
+
+func (h *AnswerHandler) Handle(w http.ResponseWriter, r *http.Request) error {
+	if err := h.process(w, r); err != nil {
+		return response.HandleError(err, w, r)
 	}
 
 	return nil
 }
 
+func (h *AnswerHandler) process(w http.ResponseWriter, r *http.Request) error {
+	codec, err := response.Negotiate(r, cardCodecs...)
+	if err != nil {
+		return err
+	}
+
+	vars := mux.Vars(r)
+
+	id, err := strconv.Atoi(vars["id"])
+	if err != nil {
+		return response.ErrBadRequest
+	}
+
+	var f card.AnswerForm
+	if err := response.Decode(r, &f); err != nil {
+		return err
+	}
+
+	card, err := h.Service.Answer(r.Context(), id, &f)
+	if err != nil {
+		return fmt.Errorf("answer: %w", err)
+	}
+
+	return response.Write(w, codec, http.StatusOK, card)
+}
+
 // DeleteHandler for delete requests.
 type DeleteHandler struct {
 	Service
@@ -139,7 +259,7 @@ type DeleteHandler struct {
 
 func (h *DeleteHandler) Handle(w http.ResponseWriter, r *http.Request) error {
 	if err := h.process(w, r); err != nil {
-		return response.HandleError(err, w)
+		return response.HandleError(err, w, r)
 	}
 
 	return nil
@@ -163,12 +283,18 @@ func (h *DeleteHandler) process(w http.ResponseWriter, r *http.Request) error {
 // Prepare prepares routes to use.
 func Prepare(subrouter *mux.Router, service Service, middleware func(handler.Handler) http.Handler) {
 	create := CreateHandler{service}
+	imp := ImportHandler{service}
 	find := FindHandler{service}
+	list := ListHandler{service}
 	update := UpdateHandler{service}
+	answer := AnswerHandler{service}
 	delete := DeleteHandler{service}
 
 	subrouter.Handle("", middleware(&create)).Methods(http.MethodPost)
+	subrouter.Handle("", middleware(&list)).Methods(http.MethodGet)
+	subrouter.Handle("/import", middleware(&imp)).Methods(http.MethodPost)
 	subrouter.Handle("/{id}", middleware(&find)).Methods(http.MethodGet)
 	subrouter.Handle("/{id}", middleware(&update)).Methods(http.MethodPut)
 	subrouter.Handle("/{id}", middleware(&delete)).Methods(http.MethodDelete)
+	subrouter.Handle("/{id}/answers", middleware(&answer)).Methods(http.MethodPost)
 }
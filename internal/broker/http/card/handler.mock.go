@@ -87,6 +87,21 @@ func (mr *MockServiceMockRecorder) Create(ctx, f interface{}) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockService)(nil).Create), ctx, f)
 }
 
+// Import mocks base method
+func (m *MockService) Import(ctx context.Context, forms []card.Form) (*card.Cards, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Import", ctx, forms)
+	ret0, _ := ret[0].(*card.Cards)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Import indicates an expected call of Import
+func (mr *MockServiceMockRecorder) Import(ctx, forms interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Import", reflect.TypeOf((*MockService)(nil).Import), ctx, forms)
+}
+
 // Find mocks base method
 func (m *MockService) Find(ctx context.Context, id int) (*card.Card, error) {
 	m.ctrl.T.Helper()
@@ -102,6 +117,21 @@ func (mr *MockServiceMockRecorder) Find(ctx, id interface{}) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Find", reflect.TypeOf((*MockService)(nil).Find), ctx, id)
 }
 
+// List mocks base method
+func (m *MockService) List(ctx context.Context, userID int) (*card.Cards, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "List", ctx, userID)
+	ret0, _ := ret[0].(*card.Cards)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// List indicates an expected call of List
+func (mr *MockServiceMockRecorder) List(ctx, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*MockService)(nil).List), ctx, userID)
+}
+
 // Update mocks base method
 func (m *MockService) Update(ctx context.Context, id int, f *card.Form) (*card.Card, error) {
 	m.ctrl.T.Helper()
@@ -117,6 +147,21 @@ func (mr *MockServiceMockRecorder) Update(ctx, id, f interface{}) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockService)(nil).Update), ctx, id, f)
 }
 
+// Answer mocks base method
+func (m *MockService) Answer(ctx context.Context, id int, f *card.AnswerForm) (*card.Card, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Answer", ctx, id, f)
+	ret0, _ := ret[0].(*card.Card)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Answer indicates an expected call of Answer
+func (mr *MockServiceMockRecorder) Answer(ctx, id, f interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Answer", reflect.TypeOf((*MockService)(nil).Answer), ctx, id, f)
+}
+
 // Delete mocks base method
 func (m *MockService) Delete(ctx context.Context, id int) error {
 	m.ctrl.T.Helper()
@@ -6,6 +6,7 @@ import (
 	"net/http/httptest"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/dipress/cards/internal/card"
 	"github.com/dipress/cards/internal/validation"
@@ -17,14 +18,38 @@ func TestCreateHandler(t *testing.T) {
 	tests := []struct {
 		name        string
 		serviceFunc func(mock *MockService)
+		header      map[string]string
 		code        int
+		contentType string
 	}{
 		{
 			name: "ok",
 			serviceFunc: func(m *MockService) {
 				m.EXPECT().Create(gomock.Any(), gomock.Any()).Return(&card.Card{}, nil)
 			},
-			code: http.StatusOK,
+			code:        http.StatusOK,
+			contentType: "application/json",
+		},
+		{
+			name: "msgpack",
+			serviceFunc: func(m *MockService) {
+				m.EXPECT().Create(gomock.Any(), gomock.Any()).Return(&card.Card{}, nil)
+			},
+			header:      map[string]string{"Accept": "application/msgpack, application/json;q=0.5"},
+			code:        http.StatusOK,
+			contentType: "application/msgpack",
+		},
+		{
+			name:        "not acceptable",
+			serviceFunc: func(m *MockService) {},
+			header:      map[string]string{"Accept": "text/csv"},
+			code:        http.StatusNotAcceptable,
+		},
+		{
+			name:        "unsupported media type",
+			serviceFunc: func(m *MockService) {},
+			header:      map[string]string{"Content-Type": "application/xml"},
+			code:        http.StatusUnsupportedMediaType,
 		},
 		{
 			name: "validation",
@@ -58,6 +83,83 @@ func TestCreateHandler(t *testing.T) {
 			w := httptest.NewRecorder()
 
 			r := httptest.NewRequest(http.MethodPost, "http://example.com", strings.NewReader("{}"))
+			for k, v := range tc.header {
+				r.Header.Set(k, v)
+			}
+
+			err := h.Handle(w, r)
+			if w.Code != tc.code {
+				t.Errorf("unexpected code: %d expected %d error: %v", w.Code, tc.code, err)
+			}
+
+			if ct := w.Header().Get("Content-Type"); tc.contentType != "" && ct != tc.contentType {
+				t.Errorf("unexpected Content-Type header: %s expected %s", ct, tc.contentType)
+			}
+		})
+	}
+}
+
+func TestImportHandler(t *testing.T) {
+	tests := []struct {
+		name        string
+		body        string
+		serviceFunc func(mock *MockService)
+		code        int
+	}{
+		{
+			name: "ok",
+			body: `{"cards":[{"user_id":1,"word":"do"}]}`,
+			serviceFunc: func(m *MockService) {
+				m.EXPECT().Import(gomock.Any(), []card.Form{{UserID: 1, Word: "do"}}).Return(&card.Cards{}, nil)
+			},
+			code: http.StatusOK,
+		},
+		{
+			name:        "no cards",
+			body:        `{"cards":[]}`,
+			serviceFunc: func(m *MockService) {},
+			code:        http.StatusBadRequest,
+		},
+		{
+			name:        "too many cards",
+			body:        `{"cards":[` + strings.Repeat(`{"user_id":1},`, card.MaxImport) + `{"user_id":1}]}`,
+			serviceFunc: func(m *MockService) {},
+			code:        http.StatusBadRequest,
+		},
+		{
+			name: "validation",
+			body: `{"cards":[{"user_id":1}]}`,
+			serviceFunc: func(m *MockService) {
+				var ves validation.Errors
+				m.EXPECT().Import(gomock.Any(), gomock.Any()).Return(nil, ves)
+			},
+			code: http.StatusUnprocessableEntity,
+		},
+		{
+			name: "internal error",
+			body: `{"cards":[{"user_id":1}]}`,
+			serviceFunc: func(m *MockService) {
+				m.EXPECT().Import(gomock.Any(), gomock.Any()).Return(nil, errors.New("mock error"))
+			},
+			code: http.StatusInternalServerError,
+		},
+	}
+
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			service := NewMockService(ctrl)
+			tc.serviceFunc(service)
+
+			h := ImportHandler{service}
+			w := httptest.NewRecorder()
+
+			r := httptest.NewRequest(http.MethodPost, "http://example.com", strings.NewReader(tc.body))
 
 			err := h.Handle(w, r)
 			if w.Code != tc.code {
@@ -121,6 +223,89 @@ func TestFindHandler(t *testing.T) {
 	}
 }
 
+func TestListHandler(t *testing.T) {
+	tests := []struct {
+		name        string
+		target      string
+		serviceFunc func(mock *MockService)
+		accept      string
+		code        int
+		body        string
+	}{
+		{
+			name:   "ok",
+			target: "http://example.com?user_id=1",
+			serviceFunc: func(m *MockService) {
+				m.EXPECT().List(gomock.Any(), 1).Return(&card.Cards{}, nil)
+			},
+			code: http.StatusOK,
+		},
+		{
+			name:   "csv",
+			target: "http://example.com?user_id=1",
+			serviceFunc: func(m *MockService) {
+				m.EXPECT().List(gomock.Any(), 1).Return(&card.Cards{Cards: []card.Card{{
+					ID:            1,
+					UserID:        1,
+					Word:          "do",
+					Transcription: "do͞o",
+					Translation:   "делать",
+					CreatedAt:     time.Date(2020, 3, 1, 12, 0, 0, 0, time.UTC),
+					UpdatedAt:     time.Date(2020, 3, 1, 12, 0, 0, 0, time.UTC),
+				}}}, nil)
+			},
+			accept: "text/csv",
+			code:   http.StatusOK,
+			body: "id,user_id,word,transcription,translation,correct_answers,wrong_answers,answered_at,created_at,updated_at\n" +
+				"1,1,do,do͞o,делать,0,0,,2020-03-01T12:00:00Z,2020-03-01T12:00:00Z\n",
+		},
+		{
+			name:        "bad request",
+			target:      "http://example.com",
+			serviceFunc: func(m *MockService) {},
+			code:        http.StatusBadRequest,
+		},
+		{
+			name:   "internal error",
+			target: "http://example.com?user_id=1",
+			serviceFunc: func(m *MockService) {
+				m.EXPECT().List(gomock.Any(), gomock.Any()).Return(nil, errors.New("mock error"))
+			},
+			code: http.StatusInternalServerError,
+		},
+	}
+
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			service := NewMockService(ctrl)
+			tc.serviceFunc(service)
+
+			h := ListHandler{service}
+			w := httptest.NewRecorder()
+
+			r := httptest.NewRequest(http.MethodGet, tc.target, nil)
+			if tc.accept != "" {
+				r.Header.Set("Accept", tc.accept)
+			}
+
+			err := h.Handle(w, r)
+			if w.Code != tc.code {
+				t.Errorf("unexpected code: %d expected %d error: %v", w.Code, tc.code, err)
+			}
+
+			if tc.body != "" && w.Body.String() != tc.body {
+				t.Errorf("unexpected body:\n\t\t%s\nexpected:\n\t\t%s", w.Body.String(), tc.body)
+			}
+		})
+	}
+}
+
 func TestUpdateHandler(t *testing.T) {
 	tests := []struct {
 		name        string
@@ -183,6 +368,70 @@ func TestUpdateHandler(t *testing.T) {
 	}
 }
 
+func TestAnswerHandler(t *testing.T) {
+	tests := []struct {
+		name        string
+		body        string
+		serviceFunc func(mock *MockService)
+		code        int
+	}{
+		{
+			name: "ok",
+			body: `{"correct": true}`,
+			serviceFunc: func(m *MockService) {
+				m.EXPECT().Answer(gomock.Any(), 1, &card.AnswerForm{Correct: true}).Return(&card.Card{}, nil)
+			},
+			code: http.StatusOK,
+		},
+		{
+			name:        "bad request",
+			body:        `{"correct": "yes"}`,
+			serviceFunc: func(m *MockService) {},
+			code:        http.StatusBadRequest,
+		},
+		{
+			name: "not found error",
+			body: `{"correct": false}`,
+			serviceFunc: func(m *MockService) {
+				m.EXPECT().Answer(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil, card.ErrNotFound)
+			},
+			code: http.StatusNotFound,
+		},
+		{
+			name: "internal error",
+			body: `{"correct": false}`,
+			serviceFunc: func(m *MockService) {
+				m.EXPECT().Answer(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil, errors.New("mock error"))
+			},
+			code: http.StatusInternalServerError,
+		},
+	}
+
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			service := NewMockService(ctrl)
+			tc.serviceFunc(service)
+
+			h := AnswerHandler{service}
+			w := httptest.NewRecorder()
+
+			r := httptest.NewRequest(http.MethodPost, "http://example.com", strings.NewReader(tc.body))
+			r = mux.SetURLVars(r, map[string]string{"id": "1"})
+
+			err := h.Handle(w, r)
+			if w.Code != tc.code {
+				t.Errorf("unexpected code: %d expected %d error: %v", w.Code, tc.code, err)
+			}
+		})
+	}
+}
+
 func TestDeleteHandler(t *testing.T) {
 	tests := []struct {
 		name        string
@@ -0,0 +1,233 @@
+package http
+
+import (
+	"bytes"
+	"compress/gzip"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/andybalholm/brotli"
+	"github.com/dipress/cards/internal/broker/http/handler"
+)
+
+const (
+	encodingBrotli   = "br"
+	encodingGzip     = "gzip"
+	encodingIdentity = "identity"
+
+	brotliLevel = 5
+)
+
+// encoders are ordered by preference when
+// the client accepts them with the same quality.
+var encoders = []string{encodingBrotli, encodingGzip}
+
+// resetWriteCloser is a compressor which can be reused.
+type resetWriteCloser interface {
+	io.WriteCloser
+	Reset(io.Writer)
+}
+
+var compressors = map[string]*sync.Pool{
+	encodingBrotli: {New: func() interface{} {
+		return brotli.NewWriterLevel(nil, brotliLevel)
+	}},
+	encodingGzip: {New: func() interface{} {
+		return gzip.NewWriter(nil)
+	}},
+}
+
+// compressMiddleware compresses responses which are at
+// least minSize bytes long by the Accept-Encoding header.
+func compressMiddleware(minSize int) handler.Middleware {
+	return func(next handler.Handler) handler.Handler {
+		h := handler.Func(func(w http.ResponseWriter, r *http.Request) error {
+			w.Header().Add("Vary", "Accept-Encoding")
+
+			encoding := acceptEncoding(r.Header.Get("Accept-Encoding"))
+			if encoding == "" || r.Method == http.MethodHead {
+				return next.Handle(w, r)
+			}
+
+			cw := compressWriter{
+				ResponseWriter: w,
+				encoding:       encoding,
+				minSize:        minSize,
+			}
+			defer cw.Close()
+
+			return next.Handle(&cw, r)
+		})
+
+		return h
+	}
+}
+
+// acceptEncoding picks a supported encoding with the highest
+// quality, empty string means the response isn't compressed.
+func acceptEncoding(header string) string {
+	qs := make(map[string]float64)
+
+	for _, part := range strings.Split(header, ",") {
+		coding, q := strings.TrimSpace(part), 1.0
+		if i := strings.Index(coding, ";"); i >= 0 {
+			param := strings.TrimSpace(coding[i+1:])
+			coding = strings.TrimSpace(coding[:i])
+
+			if !strings.HasPrefix(param, "q=") {
+				continue
+			}
+
+			v, err := strconv.ParseFloat(strings.TrimPrefix(param, "q="), 64)
+			if err != nil {
+				continue
+			}
+			q = v
+		}
+
+		qs[strings.ToLower(coding)] = q
+	}
+
+	var (
+		best  string
+		bestQ float64
+	)
+
+	for _, e := range encoders {
+		q, ok := qs[e]
+		if !ok {
+			q, ok = qs["*"]
+		}
+
+		if ok && q > bestQ {
+			best, bestQ = e, q
+		}
+	}
+
+	return best
+}
+
+// compressWriter buffers the response until it reaches minSize,
+// short responses are written as is.
+type compressWriter struct {
+	http.ResponseWriter
+	encoding string
+	minSize  int
+
+	code        int
+	buf         bytes.Buffer
+	compressor  resetWriteCloser
+	wroteHeader bool
+}
+
+// WriteHeader implements http.ResponseWriter interface,
+// the header is sent when compression is decided.
+func (cw *compressWriter) WriteHeader(code int) {
+	if cw.code == 0 {
+		cw.code = code
+	}
+}
+
+// Write implements http.ResponseWriter interface.
+func (cw *compressWriter) Write(b []byte) (int, error) {
+	if cw.code == 0 {
+		cw.code = http.StatusOK
+	}
+
+	if cw.compressor != nil {
+		return cw.compressor.Write(b)
+	}
+
+	if cw.wroteHeader {
+		return cw.ResponseWriter.Write(b)
+	}
+
+	cw.buf.Write(b)
+	if cw.buf.Len() >= cw.minSize {
+		if err := cw.start(true); err != nil {
+			return 0, err
+		}
+	}
+
+	return len(b), nil
+}
+
+// Flush implements http.Flusher interface, streamed
+// responses are compressed regardless of their size.
+func (cw *compressWriter) Flush() {
+	if !cw.wroteHeader {
+		if cw.code == 0 {
+			cw.code = http.StatusOK
+		}
+
+		if err := cw.start(true); err != nil {
+			return
+		}
+	}
+
+	if f, ok := cw.compressor.(interface{ Flush() error }); ok {
+		f.Flush()
+	}
+
+	if f, ok := cw.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+// start sends the header and the buffered body.
+func (cw *compressWriter) start(compress bool) error {
+	h := cw.Header()
+	compress = compress && h.Get("Content-Encoding") == "" && bodyAllowed(cw.code)
+
+	if compress {
+		h.Del("Content-Length")
+		h.Set("Content-Encoding", cw.encoding)
+
+		cw.compressor = compressors[cw.encoding].Get().(resetWriteCloser)
+		cw.compressor.Reset(cw.ResponseWriter)
+	}
+
+	cw.ResponseWriter.WriteHeader(cw.code)
+	cw.wroteHeader = true
+
+	if cw.buf.Len() == 0 {
+		return nil
+	}
+
+	var w io.Writer = cw.ResponseWriter
+	if cw.compressor != nil {
+		w = cw.compressor
+	}
+
+	_, err := cw.buf.WriteTo(w)
+
+	return err
+}
+
+// Close writes a short response as is or finishes the compressed one.
+func (cw *compressWriter) Close() error {
+	if !cw.wroteHeader {
+		if cw.code == 0 {
+			return nil
+		}
+
+		return cw.start(false)
+	}
+
+	if cw.compressor == nil {
+		return nil
+	}
+
+	err := cw.compressor.Close()
+	compressors[cw.encoding].Put(cw.compressor)
+	cw.compressor = nil
+
+	return err
+}
+
+func bodyAllowed(code int) bool {
+	return code >= http.StatusOK && code != http.StatusNoContent && code != http.StatusNotModified
+}
@@ -0,0 +1,169 @@
+package http
+
+import (
+	"compress/gzip"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/andybalholm/brotli"
+	"github.com/dipress/cards/internal/broker/http/handler"
+	"github.com/dipress/cards/internal/broker/http/response"
+	"github.com/dipress/cards/internal/card"
+)
+
+func Test_acceptEncoding(t *testing.T) {
+	tests := []struct {
+		header string
+		expect string
+	}{
+		{"", ""},
+		{"gzip", encodingGzip},
+		{"gzip, deflate, br", encodingBrotli},
+		{"gzip;q=1, br;q=0.5", encodingGzip},
+		{"br;q=0", ""},
+		{"*", encodingBrotli},
+		{"*;q=0.5, br;q=0", encodingGzip},
+		{"identity", ""},
+	}
+
+	for _, tc := range tests {
+		if got := acceptEncoding(tc.header); got != tc.expect {
+			t.Errorf("unexpected encoding for %q: %q expected %q", tc.header, got, tc.expect)
+		}
+	}
+}
+
+func Test_compressMiddleware(t *testing.T) {
+	large := strings.Repeat("card ", 100)
+
+	tests := []struct {
+		name           string
+		acceptEncoding string
+		next           handler.Func
+		code           int
+		encoding       string
+		body           string
+	}{
+		{
+			name:           "gzip",
+			acceptEncoding: "gzip",
+			next: func(w http.ResponseWriter, r *http.Request) error {
+				w.Header().Set("Content-Length", "500")
+				io.WriteString(w, large)
+				return nil
+			},
+			code:     http.StatusOK,
+			encoding: encodingGzip,
+			body:     large,
+		},
+		{
+			name:           "brotli",
+			acceptEncoding: "gzip, br",
+			next: func(w http.ResponseWriter, r *http.Request) error {
+				w.WriteHeader(http.StatusCreated)
+				io.WriteString(w, large)
+				return nil
+			},
+			code:     http.StatusCreated,
+			encoding: encodingBrotli,
+			body:     large,
+		},
+		{
+			name:           "short response",
+			acceptEncoding: "gzip",
+			next: func(w http.ResponseWriter, r *http.Request) error {
+				io.WriteString(w, "card")
+				return nil
+			},
+			code: http.StatusOK,
+			body: "card",
+		},
+		{
+			name: "not accepted",
+			next: func(w http.ResponseWriter, r *http.Request) error {
+				io.WriteString(w, large)
+				return nil
+			},
+			code: http.StatusOK,
+			body: large,
+		},
+		{
+			name:           "error response",
+			acceptEncoding: "gzip",
+			next: func(w http.ResponseWriter, r *http.Request) error {
+				return response.HandleError(card.ErrNotFound, w, r)
+			},
+			code: http.StatusNotFound,
+			body: `{"message":"not found"}` + "\n",
+		},
+		{
+			name:           "streamed response",
+			acceptEncoding: "gzip",
+			next: func(w http.ResponseWriter, r *http.Request) error {
+				io.WriteString(w, "card")
+				w.(http.Flusher).Flush()
+				io.WriteString(w, "card")
+				return nil
+			},
+			code:     http.StatusOK,
+			encoding: encodingGzip,
+			body:     "cardcard",
+		},
+	}
+
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			req := httptest.NewRequest(http.MethodGet, "http://example.com", nil)
+			if tc.acceptEncoding != "" {
+				req.Header.Set("Accept-Encoding", tc.acceptEncoding)
+			}
+			rec := httptest.NewRecorder()
+
+			compressMiddleware(256)(tc.next).Handle(rec, req)
+
+			if rec.Code != tc.code {
+				t.Errorf("unexpected code: %d expected %d", rec.Code, tc.code)
+			}
+
+			if got := rec.Header().Get("Content-Encoding"); got != tc.encoding {
+				t.Errorf("unexpected Content-Encoding header: %q expected %q", got, tc.encoding)
+			}
+
+			if got := rec.Header().Get("Vary"); got != "Accept-Encoding" {
+				t.Errorf("unexpected Vary header: %q", got)
+			}
+
+			if tc.encoding != "" && rec.Header().Get("Content-Length") != "" {
+				t.Errorf("unexpected Content-Length header: %s", rec.Header().Get("Content-Length"))
+			}
+
+			var body io.Reader = rec.Body
+			switch tc.encoding {
+			case encodingGzip:
+				zr, err := gzip.NewReader(rec.Body)
+				if err != nil {
+					t.Fatalf("gzip reader: %v", err)
+				}
+				body = zr
+			case encodingBrotli:
+				body = brotli.NewReader(rec.Body)
+			}
+
+			data, err := ioutil.ReadAll(body)
+			if err != nil {
+				t.Fatalf("read body: %v", err)
+			}
+
+			if string(data) != tc.body {
+				t.Errorf("unexpected body:\n\t\t%s\nexpected:\n\t\t%s", data, tc.body)
+			}
+		})
+	}
+}
@@ -0,0 +1,155 @@
+package http
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// CORS holds cross-origin resource sharing settings,
+// "*" in AllowedOrigins allows any origin without
+// credentials, AllowCredentials needs listed origins.
+type CORS struct {
+	AllowedOrigins   []string
+	AllowedMethods   []string
+	AllowedHeaders   []string
+	ExposedHeaders   []string
+	AllowCredentials bool
+	MaxAge           time.Duration
+}
+
+// cors answers preflight requests before the router
+// which would reject OPTIONS with 405 otherwise.
+type cors struct {
+	origins     map[string]bool
+	anyOrigin   bool
+	methods     map[string]bool
+	headers     map[string]bool
+	allowMethod string
+	allowHeader string
+	exposed     string
+	credentials bool
+	maxAge      string
+}
+
+func newCORS(c *CORS) *cors {
+	h := cors{
+		origins:     make(map[string]bool),
+		methods:     make(map[string]bool),
+		headers:     make(map[string]bool),
+		exposed:     strings.Join(c.ExposedHeaders, ", "),
+		credentials: c.AllowCredentials,
+	}
+
+	for _, o := range c.AllowedOrigins {
+		if o == "*" {
+			h.anyOrigin = true
+		}
+		h.origins[strings.ToLower(o)] = true
+	}
+
+	methods := make([]string, 0, len(c.AllowedMethods))
+	for _, m := range c.AllowedMethods {
+		m = strings.ToUpper(m)
+		h.methods[m] = true
+		methods = append(methods, m)
+	}
+	h.allowMethod = strings.Join(methods, ", ")
+
+	headers := make([]string, 0, len(c.AllowedHeaders))
+	for _, hd := range c.AllowedHeaders {
+		hd = http.CanonicalHeaderKey(hd)
+		h.headers[hd] = true
+		headers = append(headers, hd)
+	}
+	h.allowHeader = strings.Join(headers, ", ")
+
+	if c.MaxAge > 0 {
+		h.maxAge = strconv.Itoa(int(c.MaxAge.Seconds()))
+	}
+
+	return &h
+}
+
+func (c *cors) handler(next http.Handler) http.Handler {
+	h := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		origin := r.Header.Get("Origin")
+		if origin == "" {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		w.Header().Add("Vary", "Origin")
+
+		preflight := r.Method == http.MethodOptions && r.Header.Get("Access-Control-Request-Method") != ""
+		if preflight {
+			c.preflight(w, r, origin)
+			return
+		}
+
+		if c.allowOrigin(origin) {
+			c.setOrigin(w, origin)
+			if c.exposed != "" {
+				w.Header().Set("Access-Control-Expose-Headers", c.exposed)
+			}
+		}
+
+		next.ServeHTTP(w, r)
+	})
+
+	return h
+}
+
+func (c *cors) preflight(w http.ResponseWriter, r *http.Request, origin string) {
+	w.Header().Add("Vary", "Access-Control-Request-Method")
+	w.Header().Add("Vary", "Access-Control-Request-Headers")
+
+	method := strings.ToUpper(r.Header.Get("Access-Control-Request-Method"))
+	if !c.allowOrigin(origin) || !c.methods[method] || !c.allowHeaders(r.Header.Get("Access-Control-Request-Headers")) {
+		w.WriteHeader(http.StatusForbidden)
+		return
+	}
+
+	c.setOrigin(w, origin)
+	w.Header().Set("Access-Control-Allow-Methods", c.allowMethod)
+	if c.allowHeader != "" {
+		w.Header().Set("Access-Control-Allow-Headers", c.allowHeader)
+	}
+	if c.maxAge != "" {
+		w.Header().Set("Access-Control-Max-Age", c.maxAge)
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (c *cors) allowOrigin(origin string) bool {
+	return c.anyOrigin || c.origins[strings.ToLower(origin)]
+}
+
+func (c *cors) allowHeaders(requested string) bool {
+	for _, hd := range strings.Split(requested, ",") {
+		hd = strings.TrimSpace(hd)
+		if hd != "" && !c.headers[http.CanonicalHeaderKey(hd)] {
+			return false
+		}
+	}
+
+	return true
+}
+
+// setOrigin echoes a listed origin since "*" isn't allowed in
+// responses to credentialed requests. Any origin never gets
+// credentials, a page of any site could act as the user then.
+func (c *cors) setOrigin(w http.ResponseWriter, origin string) {
+	if c.anyOrigin {
+		w.Header().Set("Access-Control-Allow-Origin", "*")
+		return
+	}
+
+	w.Header().Set("Access-Control-Allow-Origin", origin)
+
+	if c.credentials {
+		w.Header().Set("Access-Control-Allow-Credentials", "true")
+	}
+}
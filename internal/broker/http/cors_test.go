@@ -0,0 +1,167 @@
+package http
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestNewServerCORS(t *testing.T) {
+	srv := NewServer("", testLogger(t), &Services{}, WithCORS(CORS{
+		AllowedOrigins: []string{"https://app.example.com"},
+		AllowedMethods: []string{"get", "post"},
+		AllowedHeaders: []string{"content-type", "X-Request-ID"},
+		ExposedHeaders: []string{"X-Request-ID"},
+		MaxAge:         10 * time.Minute,
+	}))
+
+	tests := []struct {
+		name          string
+		method        string
+		origin        string
+		requestMethod string
+		requestHeader string
+		code          int
+		headers       map[string]string
+	}{
+		{
+			name:          "preflight",
+			method:        http.MethodOptions,
+			origin:        "https://app.example.com",
+			requestMethod: http.MethodPost,
+			requestHeader: "Content-Type, x-request-id",
+			code:          http.StatusNoContent,
+			headers: map[string]string{
+				"Access-Control-Allow-Origin":  "https://app.example.com",
+				"Access-Control-Allow-Methods": "GET, POST",
+				"Access-Control-Allow-Headers": "Content-Type, X-Request-Id",
+				"Access-Control-Max-Age":       "600",
+			},
+		},
+		{
+			name:          "preflight from unknown origin",
+			method:        http.MethodOptions,
+			origin:        "https://evil.example.com",
+			requestMethod: http.MethodPost,
+			code:          http.StatusForbidden,
+			headers: map[string]string{
+				"Access-Control-Allow-Origin": "",
+			},
+		},
+		{
+			name:          "preflight with not allowed method",
+			method:        http.MethodOptions,
+			origin:        "https://app.example.com",
+			requestMethod: http.MethodDelete,
+			code:          http.StatusForbidden,
+		},
+		{
+			name:          "preflight with not allowed header",
+			method:        http.MethodOptions,
+			origin:        "https://app.example.com",
+			requestMethod: http.MethodGet,
+			requestHeader: "Authorization",
+			code:          http.StatusForbidden,
+		},
+		{
+			name:   "actual request",
+			method: http.MethodGet,
+			origin: "https://app.example.com",
+			code:   http.StatusOK,
+			headers: map[string]string{
+				"Access-Control-Allow-Origin":   "https://app.example.com",
+				"Access-Control-Expose-Headers": "X-Request-ID",
+				"Vary":                          "Origin",
+			},
+		},
+		{
+			name:   "same origin request",
+			method: http.MethodGet,
+			code:   http.StatusOK,
+			headers: map[string]string{
+				"Access-Control-Allow-Origin": "",
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			r := httptest.NewRequest(tc.method, "/healthz", nil)
+			if tc.origin != "" {
+				r.Header.Set("Origin", tc.origin)
+			}
+			if tc.requestMethod != "" {
+				r.Header.Set("Access-Control-Request-Method", tc.requestMethod)
+			}
+			if tc.requestHeader != "" {
+				r.Header.Set("Access-Control-Request-Headers", tc.requestHeader)
+			}
+			w := httptest.NewRecorder()
+
+			srv.Handler.ServeHTTP(w, r)
+
+			if w.Code != tc.code {
+				t.Errorf("unexpected code: %d expected %d", w.Code, tc.code)
+			}
+
+			for k, v := range tc.headers {
+				if got := w.Header().Get(k); got != v {
+					t.Errorf("unexpected %s header: %q expected %q", k, got, v)
+				}
+			}
+		})
+	}
+}
+
+func Test_corsCredentials(t *testing.T) {
+	tests := []struct {
+		name        string
+		origins     []string
+		origin      string
+		credentials string
+	}{
+		{
+			name:        "listed origin",
+			origins:     []string{"https://app.example.com"},
+			origin:      "https://app.example.com",
+			credentials: "true",
+		},
+		{
+			name:    "any origin",
+			origins: []string{"*"},
+			origin:  "*",
+		},
+	}
+
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			c := newCORS(&CORS{
+				AllowedOrigins:   tc.origins,
+				AllowCredentials: true,
+			})
+
+			next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {})
+
+			r := httptest.NewRequest(http.MethodGet, "/", nil)
+			r.Header.Set("Origin", "https://app.example.com")
+			w := httptest.NewRecorder()
+
+			c.handler(next).ServeHTTP(w, r)
+
+			if got := w.Header().Get("Access-Control-Allow-Origin"); got != tc.origin {
+				t.Errorf("unexpected Access-Control-Allow-Origin header: %q expected %q", got, tc.origin)
+			}
+
+			if got := w.Header().Get("Access-Control-Allow-Credentials"); got != tc.credentials {
+				t.Errorf("unexpected Access-Control-Allow-Credentials header: %q expected %q", got, tc.credentials)
+			}
+		})
+	}
+}
@@ -0,0 +1,134 @@
+package health
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/gorilla/mux"
+)
+
+const (
+	statusOK          = "ok"
+	statusUnavailable = "unavailable"
+
+	checkTimeout = 2 * time.Second
+)
+
+// Checker checks a dependency of the server.
+type Checker interface {
+	Check(ctx context.Context) error
+}
+
+// CheckerFunc type is an adapter to allow the use of
+// ordinary functions as checkers.
+type CheckerFunc func(ctx context.Context) error
+
+// Check implements Checker interface.
+func (f CheckerFunc) Check(ctx context.Context) error {
+	return f(ctx)
+}
+
+// Check is a named dependency check.
+type Check struct {
+	Name    string
+	Checker Checker
+}
+
+// Logger logs failed checks.
+type Logger interface {
+	Error(err error, extra map[string]interface{})
+}
+
+type checkResponse struct {
+	Status string `json:"status"`
+}
+
+type statusResponse struct {
+	Status string                   `json:"status"`
+	Checks map[string]checkResponse `json:"checks,omitempty"`
+}
+
+// LiveHandler reports that the process is up.
+type LiveHandler struct{}
+
+// ServeHTTP implements http.Handler interface.
+func (h *LiveHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	writeStatus(w, http.StatusOK, &statusResponse{Status: statusOK})
+}
+
+// ReadyHandler reports whether all dependencies are available, errors
+// of the checks go to the log only as they may tell hosts of the DSNs.
+type ReadyHandler struct {
+	Checks []Check
+	Logger Logger
+}
+
+// ServeHTTP implements http.Handler interface.
+func (h *ReadyHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(r.Context(), checkTimeout)
+	defer cancel()
+
+	resp := statusResponse{
+		Status: statusOK,
+		Checks: make(map[string]checkResponse, len(h.Checks)),
+	}
+
+	var (
+		mu sync.Mutex
+		wg sync.WaitGroup
+	)
+
+	for _, c := range h.Checks {
+		wg.Add(1)
+		go func(c Check) {
+			defer wg.Done()
+
+			cr := checkResponse{Status: statusOK}
+			if err := c.Checker.Check(ctx); err != nil {
+				cr = checkResponse{Status: statusUnavailable}
+				h.Logger.Error(fmt.Errorf("readiness check: %w", err), map[string]interface{}{
+					"check": c.Name,
+				})
+			}
+
+			mu.Lock()
+			resp.Checks[c.Name] = cr
+			if cr.Status != statusOK {
+				resp.Status = statusUnavailable
+			}
+			mu.Unlock()
+		}(c)
+	}
+
+	wg.Wait()
+
+	code := http.StatusOK
+	if resp.Status != statusOK {
+		code = http.StatusServiceUnavailable
+	}
+
+	writeStatus(w, code, &resp)
+}
+
+func writeStatus(w http.ResponseWriter, code int, resp *statusResponse) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(code)
+	json.NewEncoder(w).Encode(resp)
+}
+
+// Prepare prepares routes to use.
+func Prepare(router *mux.Router, checks []Check, logger Logger) {
+	live := LiveHandler{}
+	ready := ReadyHandler{
+		Checks: checks,
+		Logger: logger,
+	}
+
+	router.Handle("/healthz", &live).Methods(http.MethodGet, http.MethodHead)
+	router.Handle("/readyz", &ready).Methods(http.MethodGet, http.MethodHead)
+}
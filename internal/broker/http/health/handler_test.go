@@ -0,0 +1,116 @@
+package health
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// logger records errors by check name.
+type logger struct {
+	mu     sync.Mutex
+	errors map[string]error
+}
+
+func (l *logger) Error(err error, extra map[string]interface{}) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.errors[extra["check"].(string)] = err
+}
+
+func TestLiveHandler(t *testing.T) {
+	t.Parallel()
+
+	h := LiveHandler{}
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest(http.MethodGet, "http://example.com/healthz", nil)
+
+	h.ServeHTTP(w, r)
+
+	if w.Code != http.StatusOK {
+		t.Errorf("unexpected code: %d expected %d", w.Code, http.StatusOK)
+	}
+
+	expectedBody := `{"status":"ok"}`
+	if !strings.Contains(w.Body.String(), expectedBody) {
+		t.Errorf("unexpected body:\n\t\t%s\nexpected:\n\t\t%s", w.Body.String(), expectedBody)
+	}
+}
+
+func TestReadyHandler(t *testing.T) {
+	ok := CheckerFunc(func(ctx context.Context) error {
+		return nil
+	})
+
+	failed := CheckerFunc(func(ctx context.Context) error {
+		return errors.New("connection refused")
+	})
+
+	tests := []struct {
+		name   string
+		checks []Check
+		code   int
+		body   string
+		logged []string
+	}{
+		{
+			name: "ok",
+			checks: []Check{
+				{"database", ok},
+				{"migrations", ok},
+			},
+			code: http.StatusOK,
+			body: `{"status":"ok","checks":{"database":{"status":"ok"},"migrations":{"status":"ok"}}}`,
+		},
+		{
+			name: "unavailable dependency",
+			checks: []Check{
+				{"database", failed},
+				{"migrations", ok},
+			},
+			code:   http.StatusServiceUnavailable,
+			body:   `{"status":"unavailable","checks":{"database":{"status":"unavailable"},"migrations":{"status":"ok"}}}`,
+			logged: []string{"database"},
+		},
+	}
+
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			l := logger{errors: make(map[string]error)}
+			h := ReadyHandler{
+				Checks: tc.checks,
+				Logger: &l,
+			}
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest(http.MethodGet, "http://example.com/readyz", nil)
+
+			h.ServeHTTP(w, r)
+
+			if w.Code != tc.code {
+				t.Errorf("unexpected code: %d expected %d", w.Code, tc.code)
+			}
+
+			if !strings.Contains(w.Body.String(), tc.body) {
+				t.Errorf("unexpected body:\n\t\t%s\nexpected:\n\t\t%s", w.Body.String(), tc.body)
+			}
+
+			if len(l.errors) != len(tc.logged) {
+				t.Errorf("unexpected logged errors: %v expected checks %v", l.errors, tc.logged)
+			}
+
+			for _, name := range tc.logged {
+				if err := l.errors[name]; err == nil || !strings.Contains(err.Error(), "connection refused") {
+					t.Errorf("unexpected logged error of %s: %v", name, err)
+				}
+			}
+		})
+	}
+}
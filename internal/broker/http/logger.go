@@ -5,6 +5,7 @@ import (
 	"net/http"
 
 	"github.com/dipress/cards/internal/broker/http/handler"
+	kitLogger "github.com/dipress/cards/internal/kit/logger"
 )
 
 // go:generate mockgen --source=logger.go --package=http --destination=logger.mock_test.go Logger
@@ -19,13 +20,12 @@ type Logger interface {
 
 func finalizeMiddleware(logger Logger, middleware handler.Chain) func(handler.Handler) http.Handler {
 	f := func(handler handler.Handler) http.Handler {
-		wrapped := middleware.Then(handler)
+		// Handler errors are logged at the end of the chain
+		// where the request context carries the trace.
+		wrapped := middleware.Append(logErrorMiddleware(logger)).Then(handler)
 		h := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 			if err := wrapped.Handle(w, r); err != nil {
-				logger.Error(fmt.Errorf("serve http error: %w", err), map[string]interface{}{
-					"path":   r.URL.Path,
-					"method": r.Method,
-				})
+				logError(logger, r, err)
 			}
 		})
 
@@ -34,3 +34,40 @@ func finalizeMiddleware(logger Logger, middleware handler.Chain) func(handler.Ha
 
 	return f
 }
+
+// logErrorMiddleware logs the handler error and stops it there.
+func logErrorMiddleware(logger Logger) handler.Middleware {
+	return func(next handler.Handler) handler.Handler {
+		h := handler.Func(func(w http.ResponseWriter, r *http.Request) error {
+			if err := next.Handle(w, r); err != nil {
+				logError(logger, r, err)
+			}
+
+			return nil
+		})
+
+		return h
+	}
+}
+
+func logError(logger Logger, r *http.Request, err error) {
+	logger.Error(fmt.Errorf("serve http error: %w", err), requestFields(r, nil))
+}
+
+// requestFields adds the request method, path, id and trace to extra.
+func requestFields(r *http.Request, extra map[string]interface{}) map[string]interface{} {
+	fields := map[string]interface{}{
+		"path":   r.URL.Path,
+		"method": r.Method,
+	}
+
+	if id := RequestID(r.Context()); id != "" {
+		fields["request_id"] = id
+	}
+
+	for k, v := range extra {
+		fields[k] = v
+	}
+
+	return kitLogger.TraceFields(r.Context(), fields)
+}
@@ -33,7 +33,7 @@ func Test_finalizeMiddleware(t *testing.T) {
 		"method": "GET",
 	})
 
-	chain := handler.NewChain(contentTypeMiddleware)
+	chain := handler.NewChain()
 
 	finalizeMiddleware(logger, chain)(next).ServeHTTP(resp, req)
 
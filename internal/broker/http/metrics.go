@@ -0,0 +1,64 @@
+package http
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/dipress/cards/internal/broker/http/handler"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+type httpMetrics struct {
+	requests *prometheus.CounterVec
+	duration *prometheus.HistogramVec
+}
+
+// newHTTPMetrics registers collectors, it panics if
+// they are already registered like prometheus.MustRegister.
+func newHTTPMetrics(reg prometheus.Registerer) *httpMetrics {
+	m := httpMetrics{
+		requests: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: "cards",
+			Subsystem: "http",
+			Name:      "requests_total",
+			Help:      "Number of handled HTTP requests.",
+		}, []string{"method", "route", "status"}),
+		duration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: "cards",
+			Subsystem: "http",
+			Name:      "request_duration_seconds",
+			Help:      "Duration of HTTP requests.",
+			Buckets:   prometheus.DefBuckets,
+		}, []string{"method", "route", "status"}),
+	}
+
+	reg.MustRegister(m.requests, m.duration)
+
+	return &m
+}
+
+// metricsMiddleware counts requests and observes their latency
+// labeled by route template and status code.
+func metricsMiddleware(m *httpMetrics) handler.Middleware {
+	return func(next handler.Handler) handler.Handler {
+		h := handler.Func(func(w http.ResponseWriter, r *http.Request) error {
+			start := time.Now()
+			sw := newStatusWriter(w)
+
+			err := next.Handle(sw, r)
+
+			labels := prometheus.Labels{
+				"method": r.Method,
+				"route":  routeTemplate(r),
+				"status": strconv.Itoa(sw.Status()),
+			}
+			m.requests.With(labels).Inc()
+			m.duration.With(labels).Observe(time.Since(start).Seconds())
+
+			return err
+		})
+
+		return h
+	}
+}
@@ -1,17 +1,59 @@
 package http
 
 import (
+	"context"
+	"fmt"
 	"net/http"
+	"runtime/debug"
 
 	"github.com/dipress/cards/internal/broker/http/handler"
+	"github.com/dipress/cards/internal/broker/http/response"
 )
 
-// contentTypeMiddleware sets content type header.
-func contentTypeMiddleware(next handler.Handler) handler.Handler {
-	h := handler.Func(func(w http.ResponseWriter, r *http.Request) error {
-		w.Header().Set("Content-Type", "application/json")
-		return next.Handle(w, r)
-	})
+// recoverMiddleware turns a panic into internal server error
+// and logs it with the stack trace.
+func recoverMiddleware(logger Logger) handler.Middleware {
+	return func(next handler.Handler) handler.Handler {
+		h := handler.Func(func(w http.ResponseWriter, r *http.Request) (err error) {
+			sw := newStatusWriter(w)
 
-	return h
+			defer func() {
+				p := recover()
+				if p == nil {
+					return
+				}
+
+				// Let the server abort the response as it's intended.
+				if p == http.ErrAbortHandler {
+					panic(p)
+				}
+
+				logger.Error(fmt.Errorf("panic: %v", p), requestFields(r, map[string]interface{}{
+					"stack": string(debug.Stack()),
+				}))
+
+				// The response is already started, it can't be replaced.
+				if sw.status != 0 {
+					return
+				}
+
+				err = response.InternalServerError(sw, r)
+			}()
+
+			return next.Handle(sw, r)
+		})
+
+		return h
+	}
+}
+
+// contextMiddleware passes the request on with the context derived by fn.
+func contextMiddleware(fn func(context.Context) context.Context) handler.Middleware {
+	return func(next handler.Handler) handler.Handler {
+		h := handler.Func(func(w http.ResponseWriter, r *http.Request) error {
+			return next.Handle(w, r.WithContext(fn(r.Context())))
+		})
+
+		return h
+	}
 }
@@ -1,26 +1,106 @@
 package http
 
 import (
+	"context"
+	"errors"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
 
 	"github.com/dipress/cards/internal/broker/http/handler"
+	"github.com/golang/mock/gomock"
 )
 
-func Test_contentTypeMIddleware(t *testing.T) {
+func Test_recoverMiddleware(t *testing.T) {
+	tests := []struct {
+		name         string
+		next         handler.Func
+		expectedCode int
+		expectedBody string
+	}{
+		{
+			name: "panic",
+			next: func(w http.ResponseWriter, r *http.Request) error {
+				panic("mock panic")
+			},
+			expectedCode: http.StatusInternalServerError,
+			expectedBody: `{"message":"internal server error"}`,
+		},
+		{
+			name: "panic after response started",
+			next: func(w http.ResponseWriter, r *http.Request) error {
+				w.WriteHeader(http.StatusCreated)
+				panic("mock panic")
+			},
+			expectedCode: http.StatusCreated,
+		},
+	}
+
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			logger := NewMockLogger(ctrl)
+			logger.EXPECT().Error(errors.New("panic: mock panic"), gomock.Any()).Do(func(_ error, extra map[string]interface{}) {
+				if stack, _ := extra["stack"].(string); !strings.Contains(stack, "Test_recoverMiddleware") {
+					t.Errorf("expected stack trace: %s", stack)
+				}
+			})
+
+			req := httptest.NewRequest(http.MethodGet, "http://exapmle.com", nil)
+			rec := httptest.NewRecorder()
+
+			if err := recoverMiddleware(logger)(tc.next).Handle(rec, req); err != nil {
+				t.Errorf("unexpected error: %v", err)
+			}
+
+			if rec.Code != tc.expectedCode {
+				t.Errorf("unexpected code: %d expected %d", rec.Code, tc.expectedCode)
+			}
+
+			if body := rec.Body.String(); !strings.Contains(body, tc.expectedBody) {
+				t.Errorf("unexpected body:\n\t\t%s\nexpected:\n\t\t%s", body, tc.expectedBody)
+			}
+		})
+	}
+}
+
+func Test_recoverMiddlewareAbort(t *testing.T) {
+	next := handler.Func(func(w http.ResponseWriter, r *http.Request) error {
+		panic(http.ErrAbortHandler)
+	})
+
+	defer func() {
+		if p := recover(); p != http.ErrAbortHandler {
+			t.Errorf("expected http.ErrAbortHandler to be repanicked: %v", p)
+		}
+	}()
 
 	req := httptest.NewRequest(http.MethodGet, "http://exapmle.com", nil)
-	rec := httptest.NewRecorder()
+	recoverMiddleware(nil)(next).Handle(httptest.NewRecorder(), req)
+}
 
+func Test_contextMiddleware(t *testing.T) {
+	type key struct{}
+
+	var got interface{}
 	next := handler.Func(func(w http.ResponseWriter, r *http.Request) error {
+		got = r.Context().Value(key{})
 		return nil
 	})
 
-	contentTypeMiddleware(next).Handle(rec, req)
+	h := contextMiddleware(func(ctx context.Context) context.Context {
+		return context.WithValue(ctx, key{}, "derived")
+	})(next)
+
+	h.Handle(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
 
-	ct := rec.Header().Get("Content-Type")
-	if ct != "application/json" {
-		t.Errorf("expected to set application/json Content-Type header: %s", ct)
+	if got != "derived" {
+		t.Errorf("unexpected context value: %v", got)
 	}
 }
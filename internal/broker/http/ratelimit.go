@@ -0,0 +1,92 @@
+package http
+
+import (
+	"fmt"
+	"math"
+	"net"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/dipress/cards/internal/broker/http/handler"
+	"github.com/dipress/cards/internal/broker/http/response"
+	"github.com/dipress/cards/internal/kit/ratelimit"
+)
+
+// KeyFunc returns the rate limit key of an authenticated
+// client, false makes the limiter key it by ip.
+type KeyFunc func(r *http.Request) (string, bool)
+
+// UserKey keys requests by the authenticated user.
+func UserKey(r *http.Request) (string, bool) {
+	id, ok := User(r.Context())
+	if !ok {
+		return "", false
+	}
+
+	return "user:" + strconv.Itoa(id), true
+}
+
+// rateLimiter holds separate budgets for reads and writes.
+type rateLimiter struct {
+	store ratelimit.Store
+	read  ratelimit.Limit
+	write ratelimit.Limit
+	key   KeyFunc
+}
+
+// rateLimitMiddleware limits requests of every user or client ip,
+// when the store fails requests are let through.
+func rateLimitMiddleware(logger Logger, rl *rateLimiter) handler.Middleware {
+	return func(next handler.Handler) handler.Handler {
+		h := handler.Func(func(w http.ResponseWriter, r *http.Request) error {
+			class, limit := "write", rl.write
+			switch r.Method {
+			case http.MethodGet, http.MethodHead, http.MethodOptions:
+				class, limit = "read", rl.read
+			}
+
+			res, err := rl.store.Take(r.Context(), class+":"+rl.clientKey(r), limit)
+			if err != nil {
+				logger.Error(fmt.Errorf("rate limit: %w", err), requestFields(r, nil))
+				return next.Handle(w, r)
+			}
+
+			w.Header().Set("RateLimit-Limit", strconv.Itoa(res.Limit))
+			w.Header().Set("RateLimit-Remaining", strconv.Itoa(res.Remaining))
+			w.Header().Set("RateLimit-Reset", seconds(res.Reset))
+
+			if !res.Allowed {
+				w.Header().Set("Retry-After", seconds(res.RetryAfter))
+				return response.TooManyRequests(w, r)
+			}
+
+			return next.Handle(w, r)
+		})
+
+		return h
+	}
+}
+
+// clientKey identifies the client by the key function or by ip, the
+// user id header isn't authenticated and any client could pick a
+// fresh one.
+func (rl *rateLimiter) clientKey(r *http.Request) string {
+	if rl.key != nil {
+		if key, ok := rl.key(r); ok {
+			return key
+		}
+	}
+
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		host = r.RemoteAddr
+	}
+
+	return "ip:" + host
+}
+
+// seconds rounds the duration up to whole seconds.
+func seconds(d time.Duration) string {
+	return strconv.Itoa(int(math.Ceil(d.Seconds())))
+}
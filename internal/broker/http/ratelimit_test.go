@@ -0,0 +1,179 @@
+package http
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/dipress/cards/internal/broker/http/handler"
+	"github.com/dipress/cards/internal/kit/ratelimit"
+	"github.com/golang/mock/gomock"
+)
+
+type failingStore struct{}
+
+func (failingStore) Take(ctx context.Context, key string, limit ratelimit.Limit) (ratelimit.Result, error) {
+	return ratelimit.Result{}, errors.New("mock error")
+}
+
+func Test_rateLimitMiddleware(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	logger := NewMockLogger(ctrl)
+
+	next := handler.Func(func(w http.ResponseWriter, r *http.Request) error {
+		return nil
+	})
+
+	rl := rateLimiter{
+		store: ratelimit.NewMemoryStore(),
+		read:  ratelimit.Limit{Rate: 0.001, Burst: 2},
+		write: ratelimit.Limit{Rate: 0.001, Burst: 1},
+	}
+
+	h := rateLimitMiddleware(logger, &rl)(next)
+
+	tests := []struct {
+		name       string
+		method     string
+		userID     string
+		remoteAddr string
+		code       int
+		remaining  string
+		retryAfter string
+	}{
+		{
+			name:       "first read",
+			method:     http.MethodGet,
+			remoteAddr: "192.0.2.1:1234",
+			code:       http.StatusOK,
+			remaining:  "1",
+		},
+		{
+			name:       "same ip another port",
+			method:     http.MethodGet,
+			remoteAddr: "192.0.2.1:4321",
+			code:       http.StatusOK,
+			remaining:  "0",
+		},
+		{
+			name:       "reads exhausted",
+			method:     http.MethodGet,
+			remoteAddr: "192.0.2.1:1234",
+			code:       http.StatusTooManyRequests,
+			remaining:  "0",
+			retryAfter: "1000",
+		},
+		{
+			name:       "separate write budget",
+			method:     http.MethodPost,
+			remoteAddr: "192.0.2.1:1234",
+			code:       http.StatusOK,
+			remaining:  "0",
+		},
+		{
+			name:       "user header is ignored",
+			method:     http.MethodGet,
+			userID:     "7",
+			remoteAddr: "192.0.2.1:1234",
+			code:       http.StatusTooManyRequests,
+			remaining:  "0",
+			retryAfter: "1000",
+		},
+		{
+			name:       "another ip",
+			method:     http.MethodGet,
+			remoteAddr: "192.0.2.2:1234",
+			code:       http.StatusOK,
+			remaining:  "1",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			req := httptest.NewRequest(tc.method, "http://example.com", nil)
+			req.RemoteAddr = tc.remoteAddr
+			if tc.userID != "" {
+				req.Header.Set(UserIDHeader, tc.userID)
+			}
+			rec := httptest.NewRecorder()
+
+			h.Handle(rec, req)
+
+			if rec.Code != tc.code {
+				t.Errorf("unexpected code: %d expected %d", rec.Code, tc.code)
+			}
+
+			if got := rec.Header().Get("RateLimit-Remaining"); got != tc.remaining {
+				t.Errorf("unexpected RateLimit-Remaining header: %s expected %s", got, tc.remaining)
+			}
+
+			if got := rec.Header().Get("Retry-After"); got != tc.retryAfter {
+				t.Errorf("unexpected Retry-After header: %s expected %s", got, tc.retryAfter)
+			}
+		})
+	}
+
+	t.Run("store error", func(t *testing.T) {
+		logger.EXPECT().Error(gomock.Any(), gomock.Any())
+
+		h := rateLimitMiddleware(logger, &rateLimiter{store: failingStore{}})(next)
+
+		req := httptest.NewRequest(http.MethodGet, "http://example.com", nil)
+		rec := httptest.NewRecorder()
+
+		h.Handle(rec, req)
+
+		if rec.Code != http.StatusOK {
+			t.Errorf("unexpected code: %d expected %d", rec.Code, http.StatusOK)
+		}
+	})
+}
+
+func Test_rateLimiterClientKey(t *testing.T) {
+	tests := []struct {
+		name   string
+		key    KeyFunc
+		userID int
+		expect string
+	}{
+		{
+			name:   "authenticated user",
+			key:    UserKey,
+			userID: 7,
+			expect: "user:7",
+		},
+		{
+			name:   "anonymous falls back to ip",
+			key:    UserKey,
+			expect: "ip:192.0.2.1",
+		},
+		{
+			name:   "no key func",
+			userID: 7,
+			expect: "ip:192.0.2.1",
+		},
+	}
+
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			req := httptest.NewRequest(http.MethodGet, "http://example.com", nil)
+			req.RemoteAddr = "192.0.2.1:1234"
+			req.Header.Set(UserIDHeader, "8")
+			if tc.userID != 0 {
+				req = req.WithContext(WithUser(req.Context(), tc.userID))
+			}
+
+			rl := rateLimiter{key: tc.key}
+			if got := rl.clientKey(req); got != tc.expect {
+				t.Errorf("unexpected key: %s expected %s", got, tc.expect)
+			}
+		})
+	}
+}
@@ -0,0 +1,43 @@
+package http
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+
+	"github.com/dipress/cards/internal/broker/http/health"
+)
+
+// errNotReady raises when the server is starting or shutting down.
+var errNotReady = errors.New("server is not ready")
+
+// Readiness tells whether the server should receive new traffic,
+// the zero value is not ready.
+type Readiness struct {
+	ready int32
+}
+
+// SetReady marks the server as ready or not ready.
+func (r *Readiness) SetReady(ready bool) {
+	var v int32
+	if ready {
+		v = 1
+	}
+
+	atomic.StoreInt32(&r.ready, v)
+}
+
+// Ready reports whether the server is ready.
+func (r *Readiness) Ready() bool {
+	return atomic.LoadInt32(&r.ready) == 1
+}
+
+func readinessChecker(r *Readiness) health.Checker {
+	return health.CheckerFunc(func(ctx context.Context) error {
+		if !r.Ready() {
+			return errNotReady
+		}
+
+		return nil
+	})
+}
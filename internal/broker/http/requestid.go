@@ -0,0 +1,70 @@
+package http
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"net/http"
+
+	"github.com/dipress/cards/internal/broker/http/handler"
+	"github.com/dipress/cards/internal/broker/http/response"
+)
+
+const (
+	// RequestIDHeader holds id of the request.
+	RequestIDHeader = response.RequestIDHeader
+
+	maxRequestIDLength = 128
+)
+
+type requestIDKey struct{}
+
+// RequestID returns id of the request from the context.
+func RequestID(ctx context.Context) string {
+	id, _ := ctx.Value(requestIDKey{}).(string)
+	return id
+}
+
+// requestIDMiddleware honors an incoming request id or
+// generates a new one, and echoes it back in the response.
+func requestIDMiddleware(next handler.Handler) handler.Handler {
+	h := handler.Func(func(w http.ResponseWriter, r *http.Request) error {
+		id := r.Header.Get(RequestIDHeader)
+		if !validRequestID(id) {
+			id = newRequestID()
+		}
+
+		w.Header().Set(RequestIDHeader, id)
+
+		ctx := context.WithValue(r.Context(), requestIDKey{}, id)
+
+		return next.Handle(w, r.WithContext(ctx))
+	})
+
+	return h
+}
+
+// validRequestID accepts printable ASCII ids of a sane length
+// so that clients can't inject anything into the logs.
+func validRequestID(id string) bool {
+	if id == "" || len(id) > maxRequestIDLength {
+		return false
+	}
+
+	for i := 0; i < len(id); i++ {
+		if id[i] < '!' || id[i] > '~' {
+			return false
+		}
+	}
+
+	return true
+}
+
+func newRequestID() string {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return ""
+	}
+
+	return hex.EncodeToString(b)
+}
@@ -0,0 +1,72 @@
+package http
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/dipress/cards/internal/broker/http/handler"
+)
+
+func Test_requestIDMiddleware(t *testing.T) {
+	tests := []struct {
+		name     string
+		incoming string
+		keep     bool
+	}{
+		{
+			name:     "incoming",
+			incoming: "abc-123",
+			keep:     true,
+		},
+		{
+			name: "generated",
+		},
+		{
+			name:     "invalid incoming",
+			incoming: "abc 123\n",
+		},
+		{
+			name:     "too long incoming",
+			incoming: strings.Repeat("a", maxRequestIDLength+1),
+		},
+	}
+
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			var got string
+			next := handler.Func(func(w http.ResponseWriter, r *http.Request) error {
+				got = RequestID(r.Context())
+				return nil
+			})
+
+			req := httptest.NewRequest(http.MethodGet, "http://example.com", nil)
+			if tc.incoming != "" {
+				req.Header.Set(RequestIDHeader, tc.incoming)
+			}
+			rec := httptest.NewRecorder()
+
+			requestIDMiddleware(next).Handle(rec, req)
+
+			if got == "" {
+				t.Fatal("expected request id in the context")
+			}
+
+			if tc.keep && got != tc.incoming {
+				t.Errorf("unexpected request id: %s expected %s", got, tc.incoming)
+			}
+
+			if !tc.keep && got == tc.incoming {
+				t.Errorf("expected request id to be generated: %s", got)
+			}
+
+			if h := rec.Header().Get(RequestIDHeader); h != got {
+				t.Errorf("unexpected %s header: %s expected %s", RequestIDHeader, h, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,220 @@
+package response
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/vmihailenco/msgpack/v5"
+)
+
+// Media types of request and response bodies.
+const (
+	MediaJSON    = "application/json"
+	MediaMsgPack = "application/msgpack"
+	MediaCSV     = "text/csv"
+)
+
+var (
+	// ErrNotAcceptable raises when none of the accepted media types is offered.
+	ErrNotAcceptable = errors.New("not acceptable")
+	// ErrUnsupportedMediaType raises when the request body can't be decoded.
+	ErrUnsupportedMediaType = errors.New("unsupported media type")
+)
+
+// Codec encodes response and decodes request bodies in a media type.
+type Codec interface {
+	MediaType() string
+	Encode(w io.Writer, v interface{}) error
+	Decode(r io.Reader, v interface{}) error
+}
+
+// CSVMarshaler is implemented by collections which can be written as CSV.
+type CSVMarshaler interface {
+	MarshalCSV() ([][]string, error)
+}
+
+// Codecs.
+var (
+	JSON    Codec = jsonCodec{}
+	MsgPack Codec = msgpackCodec{}
+	CSV     Codec = csvCodec{}
+)
+
+// decoders are used for request bodies.
+var decoders = map[string]Codec{
+	MediaJSON:                 JSON,
+	MediaMsgPack:              MsgPack,
+	"application/x-msgpack":   MsgPack,
+	"application/vnd.msgpack": MsgPack,
+}
+
+type jsonCodec struct{}
+
+func (jsonCodec) MediaType() string { return MediaJSON }
+
+func (jsonCodec) Encode(w io.Writer, v interface{}) error {
+	return json.NewEncoder(w).Encode(v)
+}
+
+func (jsonCodec) Decode(r io.Reader, v interface{}) error {
+	return json.NewDecoder(r).Decode(v)
+}
+
+type msgpackCodec struct{}
+
+func (msgpackCodec) MediaType() string { return MediaMsgPack }
+
+// Encode uses json tags to keep field names the same in both formats.
+func (msgpackCodec) Encode(w io.Writer, v interface{}) error {
+	enc := msgpack.NewEncoder(w)
+	enc.SetCustomStructTag("json")
+	return enc.Encode(v)
+}
+
+func (msgpackCodec) Decode(r io.Reader, v interface{}) error {
+	dec := msgpack.NewDecoder(r)
+	dec.SetCustomStructTag("json")
+	return dec.Decode(v)
+}
+
+type csvCodec struct{}
+
+func (csvCodec) MediaType() string { return MediaCSV }
+
+func (csvCodec) Encode(w io.Writer, v interface{}) error {
+	m, ok := v.(CSVMarshaler)
+	if !ok {
+		return fmt.Errorf("%T can't be written as csv: %w", v, ErrNotAcceptable)
+	}
+
+	records, err := m.MarshalCSV()
+	if err != nil {
+		return fmt.Errorf("marshal csv: %w", err)
+	}
+
+	cw := csv.NewWriter(w)
+	if err := cw.WriteAll(records); err != nil {
+		return fmt.Errorf("write all: %w", err)
+	}
+
+	return nil
+}
+
+func (csvCodec) Decode(r io.Reader, v interface{}) error {
+	return ErrUnsupportedMediaType
+}
+
+// Negotiate picks one of the offered codecs by the Accept header,
+// the first one is used if the header is missing or accepts anything.
+func Negotiate(r *http.Request, offers ...Codec) (Codec, error) {
+	accept := r.Header.Get("Accept")
+	if accept == "" {
+		return offers[0], nil
+	}
+
+	for _, mediaRange := range parseAccept(accept) {
+		for _, c := range offers {
+			if matchMedia(mediaRange, c.MediaType()) {
+				return c, nil
+			}
+		}
+	}
+
+	return nil, ErrNotAcceptable
+}
+
+type acceptRange struct {
+	media string
+	q     float64
+}
+
+// parseAccept returns accepted media ranges ordered by quality,
+// ranges with q=0 are dropped.
+func parseAccept(accept string) []string {
+	ranges := make([]acceptRange, 0)
+
+	for _, part := range strings.Split(accept, ",") {
+		media, params, err := mime.ParseMediaType(strings.TrimSpace(part))
+		if err != nil {
+			continue
+		}
+
+		q := 1.0
+		if v, ok := params["q"]; ok {
+			if q, err = strconv.ParseFloat(v, 64); err != nil {
+				continue
+			}
+		}
+
+		if q > 0 {
+			ranges = append(ranges, acceptRange{media, q})
+		}
+	}
+
+	sort.SliceStable(ranges, func(i, j int) bool {
+		return ranges[i].q > ranges[j].q
+	})
+
+	media := make([]string, len(ranges))
+	for i, r := range ranges {
+		media[i] = r.media
+	}
+
+	return media
+}
+
+func matchMedia(mediaRange, media string) bool {
+	if mediaRange == "*/*" || mediaRange == media {
+		return true
+	}
+
+	if strings.HasSuffix(mediaRange, "/*") {
+		return strings.HasPrefix(media, strings.TrimSuffix(mediaRange, "*"))
+	}
+
+	return false
+}
+
+// Decode reads the request body by its Content-Type,
+// JSON is assumed when the header is missing.
+func Decode(r *http.Request, v interface{}) error {
+	c := JSON
+
+	if ct := r.Header.Get("Content-Type"); ct != "" {
+		media, _, err := mime.ParseMediaType(ct)
+		if err != nil {
+			return ErrUnsupportedMediaType
+		}
+
+		var ok bool
+		if c, ok = decoders[media]; !ok {
+			return ErrUnsupportedMediaType
+		}
+	}
+
+	if err := c.Decode(r.Body, v); err != nil {
+		return fmt.Errorf("decode %s: %v: %w", c.MediaType(), err, ErrBadRequest)
+	}
+
+	return nil
+}
+
+// Write responds with v encoded by the codec.
+func Write(w http.ResponseWriter, c Codec, code int, v interface{}) error {
+	w.Header().Set("Content-Type", c.MediaType())
+	w.WriteHeader(code)
+
+	if err := c.Encode(w, v); err != nil {
+		return fmt.Errorf("encode %s: %w", c.MediaType(), err)
+	}
+
+	return nil
+}
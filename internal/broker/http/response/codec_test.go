@@ -0,0 +1,164 @@
+package response
+
+import (
+	"bytes"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNegotiate(t *testing.T) {
+	tests := []struct {
+		name    string
+		accept  string
+		offers  []Codec
+		expect  Codec
+		wantErr error
+	}{
+		{
+			name:   "missing accept",
+			offers: []Codec{JSON, MsgPack},
+			expect: JSON,
+		},
+		{
+			name:   "any",
+			accept: "*/*",
+			offers: []Codec{JSON, MsgPack},
+			expect: JSON,
+		},
+		{
+			name:   "exact",
+			accept: "application/msgpack",
+			offers: []Codec{JSON, MsgPack},
+			expect: MsgPack,
+		},
+		{
+			name:   "quality",
+			accept: "application/json;q=0.5, text/csv",
+			offers: []Codec{JSON, MsgPack, CSV},
+			expect: CSV,
+		},
+		{
+			name:   "subtype wildcard",
+			accept: "text/*",
+			offers: []Codec{JSON, MsgPack, CSV},
+			expect: CSV,
+		},
+		{
+			name:    "csv isn't offered",
+			accept:  "text/csv",
+			offers:  []Codec{JSON, MsgPack},
+			wantErr: ErrNotAcceptable,
+		},
+		{
+			name:    "refused with zero quality",
+			accept:  "application/json;q=0",
+			offers:  []Codec{JSON},
+			wantErr: ErrNotAcceptable,
+		},
+	}
+
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			r := httptest.NewRequest(http.MethodGet, "http://example.com", nil)
+			if tc.accept != "" {
+				r.Header.Set("Accept", tc.accept)
+			}
+
+			c, err := Negotiate(r, tc.offers...)
+			if tc.wantErr != nil {
+				assert.True(t, errors.Is(err, tc.wantErr), "unexpected error: %v", err)
+				return
+			}
+
+			assert.Nil(t, err)
+			assert.Equal(t, tc.expect, c)
+		})
+	}
+}
+
+type form struct {
+	Word string `json:"word"`
+}
+
+func TestDecode(t *testing.T) {
+	var packed bytes.Buffer
+	if err := MsgPack.Encode(&packed, &form{Word: "do"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	tests := []struct {
+		name        string
+		contentType string
+		body        string
+		expect      form
+		wantErr     error
+	}{
+		{
+			name:   "json by default",
+			body:   `{"word":"do"}`,
+			expect: form{Word: "do"},
+		},
+		{
+			name:        "json with charset",
+			contentType: "application/json; charset=utf-8",
+			body:        `{"word":"do"}`,
+			expect:      form{Word: "do"},
+		},
+		{
+			name:        "msgpack",
+			contentType: "application/msgpack",
+			body:        packed.String(),
+			expect:      form{Word: "do"},
+		},
+		{
+			name:        "unsupported",
+			contentType: "text/csv",
+			body:        "word\ndo\n",
+			wantErr:     ErrUnsupportedMediaType,
+		},
+		{
+			name:    "malformed",
+			body:    `{"word":`,
+			wantErr: ErrBadRequest,
+		},
+	}
+
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			r := httptest.NewRequest(http.MethodPost, "http://example.com", strings.NewReader(tc.body))
+			if tc.contentType != "" {
+				r.Header.Set("Content-Type", tc.contentType)
+			}
+
+			var f form
+			err := Decode(r, &f)
+			if tc.wantErr != nil {
+				assert.True(t, errors.Is(err, tc.wantErr), "unexpected error: %v", err)
+				return
+			}
+
+			assert.Nil(t, err)
+			assert.Equal(t, tc.expect, f)
+		})
+	}
+}
+
+func TestWriteCSV(t *testing.T) {
+	t.Parallel()
+
+	rec := httptest.NewRecorder()
+
+	err := Write(rec, CSV, http.StatusOK, &form{})
+	assert.True(t, errors.Is(err, ErrNotAcceptable), "unexpected error: %v", err)
+}
@@ -0,0 +1,42 @@
+package response
+
+import (
+	"net/http"
+
+	"github.com/dipress/cards/internal/i18n"
+	"github.com/dipress/cards/internal/validation"
+	"golang.org/x/text/language"
+)
+
+// negotiateLanguage picks the language of the error message by
+// the Accept-Language header and announces it.
+func negotiateLanguage(w http.ResponseWriter, r *http.Request) language.Tag {
+	lang := i18n.Match(r.Header.Get("Accept-Language"))
+
+	w.Header().Add("Vary", "Accept-Language")
+	w.Header().Set("Content-Language", lang.String())
+
+	return lang
+}
+
+// localizeErrors translates validation errors by their codes,
+// details without a code are kept as they are.
+func localizeErrors(w http.ResponseWriter, r *http.Request, ers validation.Errors) validation.Errors {
+	lang := negotiateLanguage(w, r)
+
+	localized := validation.Errors{
+		Message: i18n.Translate(lang, i18n.ValidationFailed),
+		Details: make(map[string]string, len(ers.Details)),
+		Codes:   ers.Codes,
+	}
+
+	for field, detail := range ers.Details {
+		if code, ok := ers.Codes[field]; ok {
+			detail = i18n.Translate(lang, code)
+		}
+
+		localized.Details[field] = detail
+	}
+
+	return localized
+}
@@ -0,0 +1,83 @@
+package response
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/dipress/cards/internal/card"
+	"github.com/dipress/cards/internal/validation"
+)
+
+func TestHandleErrorLocalized(t *testing.T) {
+	ves := validation.NewErrors()
+	ves.Add("word", "required", "cannot be blank")
+	ves.Details["note"] = "free text"
+
+	tests := []struct {
+		name           string
+		acceptLanguage string
+		accept         string
+		err            error
+		language       string
+		body           string
+	}{
+		{
+			name:     "default",
+			err:      card.ErrNotFound,
+			language: "en",
+			body:     `{"message":"not found"}` + "\n",
+		},
+		{
+			name:           "russian",
+			acceptLanguage: "ru-RU,ru;q=0.9",
+			err:            card.ErrNotFound,
+			language:       "ru",
+			body:           `{"message":"не найдено"}` + "\n",
+		},
+		{
+			name:           "spanish validation",
+			acceptLanguage: "es",
+			err:            ves,
+			language:       "es",
+			body:           `{"error":"hay errores de validación","details":{"note":"free text","word":"no puede estar vacío"},"codes":{"word":"required"}}` + "\n",
+		},
+		{
+			name:           "russian problem",
+			acceptLanguage: "ru",
+			accept:         MediaProblem,
+			err:            ves,
+			language:       "ru",
+			body:           `{"type":"about:blank","title":"Unprocessable Entity","status":422,"detail":"в запросе есть ошибки","errors":{"note":"free text","word":"не может быть пустым"},"codes":{"word":"required"}}` + "\n",
+		},
+		{
+			name:           "unsupported language",
+			acceptLanguage: "de",
+			err:            ErrBadRequest,
+			language:       "en",
+			body:           `{"message":"bad request"}` + "\n",
+		},
+	}
+
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest(http.MethodGet, "http://example.com", nil)
+			r.Header.Set("Accept-Language", tc.acceptLanguage)
+			r.Header.Set("Accept", tc.accept)
+
+			HandleError(tc.err, w, r)
+
+			if got := w.Header().Get("Content-Language"); got != tc.language {
+				t.Errorf("unexpected language: %s expected %s", got, tc.language)
+			}
+
+			if w.Body.String() != tc.body {
+				t.Errorf("unexpected body:\n\t\t%s\nexpected:\n\t\t%s", w.Body.String(), tc.body)
+			}
+		})
+	}
+}
@@ -0,0 +1,66 @@
+package response
+
+import (
+	json "encoding/json"
+	"fmt"
+	"net/http"
+)
+
+const (
+	// MediaProblem is the RFC 7807 problem details media type.
+	MediaProblem = "application/problem+json"
+
+	// problemBlank is the problem type which means no more
+	// than the HTTP status code.
+	problemBlank = "about:blank"
+
+	// RequestIDHeader holds id of the request,
+	// the server echoes it on every response.
+	RequestIDHeader = "X-Request-ID"
+)
+
+// Problem is an RFC 7807 error response.
+type Problem struct {
+	Type     string            `json:"type"`
+	Title    string            `json:"title"`
+	Status   int               `json:"status"`
+	Detail   string            `json:"detail,omitempty"`
+	Instance string            `json:"instance,omitempty"`
+	Errors   map[string]string `json:"errors,omitempty"`
+	Codes    map[string]string `json:"codes,omitempty"`
+}
+
+// acceptsProblem reports whether the client asked for problem details
+// explicitly, wildcards keep the legacy error bodies.
+func acceptsProblem(r *http.Request) bool {
+	for _, media := range parseAccept(r.Header.Get("Accept")) {
+		if media == MediaProblem {
+			return true
+		}
+	}
+
+	return false
+}
+
+// writeProblem responds with problem details, the request id
+// identifies the occurrence of the problem.
+func writeProblem(w http.ResponseWriter, code int, detail string, details, codes map[string]string) error {
+	p := Problem{
+		Type:     problemBlank,
+		Title:    http.StatusText(code),
+		Status:   code,
+		Detail:   detail,
+		Instance: w.Header().Get(RequestIDHeader),
+		Errors:   details,
+		Codes:    codes,
+	}
+
+	w.Header().Set("Content-Type", MediaProblem)
+	w.WriteHeader(code)
+
+	if err := json.NewEncoder(w).Encode(&p); err != nil {
+		return fmt.Errorf("encode: %w", err)
+	}
+
+	return nil
+}
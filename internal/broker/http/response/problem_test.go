@@ -0,0 +1,102 @@
+package response
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/dipress/cards/internal/card"
+	"github.com/dipress/cards/internal/validation"
+)
+
+func TestHandleErrorProblem(t *testing.T) {
+	ves := validation.NewErrors()
+	ves.Add("word", "required", "cannot be blank")
+
+	tests := []struct {
+		name        string
+		accept      string
+		err         error
+		code        int
+		contentType string
+		body        string
+	}{
+		{
+			name:        "not found",
+			accept:      MediaProblem,
+			err:         card.ErrNotFound,
+			code:        http.StatusNotFound,
+			contentType: MediaProblem,
+			body:        `{"type":"about:blank","title":"Not Found","status":404,"detail":"not found","instance":"abc"}` + "\n",
+		},
+		{
+			name:        "validation",
+			accept:      "application/json, application/problem+json",
+			err:         ves,
+			code:        http.StatusUnprocessableEntity,
+			contentType: MediaProblem,
+			body:        `{"type":"about:blank","title":"Unprocessable Entity","status":422,"detail":"you have validation errors","instance":"abc","errors":{"word":"cannot be blank"},"codes":{"word":"required"}}` + "\n",
+		},
+		{
+			name:        "internal",
+			accept:      MediaProblem,
+			err:         errors.New("connection refused"),
+			code:        http.StatusInternalServerError,
+			contentType: MediaProblem,
+			body:        `{"type":"about:blank","title":"Internal Server Error","status":500,"detail":"internal server error","instance":"abc"}` + "\n",
+		},
+		{
+			name:        "legacy by wildcard",
+			accept:      "*/*",
+			err:         card.ErrNotFound,
+			code:        http.StatusNotFound,
+			contentType: MediaJSON,
+			body:        `{"message":"not found"}` + "\n",
+		},
+		{
+			name:        "legacy validation",
+			err:         ves,
+			code:        http.StatusUnprocessableEntity,
+			contentType: MediaJSON,
+			body:        `{"error":"you have validation errors","details":{"word":"cannot be blank"},"codes":{"word":"required"}}` + "\n",
+		},
+		{
+			name:        "problem refused",
+			accept:      "application/problem+json;q=0, application/json",
+			err:         ErrBadRequest,
+			code:        http.StatusBadRequest,
+			contentType: MediaJSON,
+			body:        `{"message":"bad request"}` + "\n",
+		},
+	}
+
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			w := httptest.NewRecorder()
+			w.Header().Set("X-Request-ID", "abc")
+
+			r := httptest.NewRequest(http.MethodGet, "http://example.com", nil)
+			if tc.accept != "" {
+				r.Header.Set("Accept", tc.accept)
+			}
+
+			HandleError(tc.err, w, r)
+
+			if w.Code != tc.code {
+				t.Errorf("unexpected code: %d expected %d", w.Code, tc.code)
+			}
+
+			if got := w.Header().Get("Content-Type"); got != tc.contentType {
+				t.Errorf("unexpected content type: %s expected %s", got, tc.contentType)
+			}
+
+			if w.Body.String() != tc.body {
+				t.Errorf("unexpected body:\n\t\t%s\nexpected:\n\t\t%s", w.Body.String(), tc.body)
+			}
+		})
+	}
+}
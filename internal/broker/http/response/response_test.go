@@ -14,7 +14,7 @@ func TestBadRequest(t *testing.T) {
 	t.Parallel()
 
 	rec := httptest.NewRecorder()
-	BadRequest(rec)
+	BadRequest(rec, httptest.NewRequest(http.MethodGet, "/", nil))
 
 	expect := http.StatusBadRequest
 	got := rec.Code
@@ -44,7 +44,7 @@ func TestValidationError(t *testing.T) {
 	ves := validation.NewErrors()
 	ves.Details["word"] = "cannot be blank"
 
-	ValidationError(rec, ves)
+	ValidationError(rec, httptest.NewRequest(http.MethodPost, "/", nil), ves)
 
 	expect := http.StatusUnprocessableEntity
 	got := rec.Code
@@ -70,7 +70,7 @@ func TestInternalServerError(t *testing.T) {
 	t.Parallel()
 
 	rec := httptest.NewRecorder()
-	InternalServerError(rec)
+	InternalServerError(rec, httptest.NewRequest(http.MethodGet, "/", nil))
 
 	expect := http.StatusInternalServerError
 	got := rec.Code
@@ -91,3 +91,29 @@ func TestInternalServerError(t *testing.T) {
 		t.Errorf("unexpected body:\n\t\t%s\nexpected:\n\t\t%s", body, expectedBody)
 	}
 }
+
+func TestTooManyRequests(t *testing.T) {
+	t.Parallel()
+
+	rec := httptest.NewRecorder()
+	TooManyRequests(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+
+	expect := http.StatusTooManyRequests
+	got := rec.Code
+
+	if got != expect {
+		t.Errorf("unexpected status code: %d expected: %d", got, expect)
+	}
+
+	body, err := ioutil.ReadAll(rec.Body)
+	if err != nil {
+		t.Errorf("failed to read recorder body: %v", err)
+		return
+	}
+
+	expectedBody := `{"message":"too many requests"}`
+
+	if !strings.Contains(string(body), expectedBody) {
+		t.Errorf("unexpected body:\n\t\t%s\nexpected:\n\t\t%s", body, expectedBody)
+	}
+}
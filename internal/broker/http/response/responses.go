@@ -7,6 +7,7 @@ import (
 	"net/http"
 
 	"github.com/dipress/cards/internal/card"
+	"github.com/dipress/cards/internal/i18n"
 	"github.com/dipress/cards/internal/validation"
 )
 
@@ -18,18 +19,22 @@ var (
 )
 
 // HandleError allows to handle default errors.
-func HandleError(err error, w http.ResponseWriter) error {
+func HandleError(err error, w http.ResponseWriter, r *http.Request) error {
 	var vErr validation.Errors
 	switch {
 	case errors.As(err, &vErr):
-		return ValidationError(w, vErr)
+		return ValidationError(w, r, vErr)
 	case errors.Is(err, ErrBadRequest):
-		return BadRequest(w)
+		return BadRequest(w, r)
 	case errors.Is(err, card.ErrNotFound):
-		return NotFound(w)
+		return NotFound(w, r)
+	case errors.Is(err, ErrNotAcceptable):
+		return NotAcceptable(w, r)
+	case errors.Is(err, ErrUnsupportedMediaType):
+		return UnsupportedMediaType(w, r)
 	}
 
-	if rErr := InternalServerError(w); rErr != nil {
+	if rErr := InternalServerError(w, r); rErr != nil {
 		return fmt.Errorf("internal error: %v: %w", rErr, err)
 	}
 
@@ -37,25 +42,44 @@ func HandleError(err error, w http.ResponseWriter) error {
 }
 
 // BadRequest responds code 400.
-func BadRequest(w http.ResponseWriter) error {
-	w.WriteHeader(http.StatusBadRequest)
-	return writeError(w, "bad request")
+func BadRequest(w http.ResponseWriter, r *http.Request) error {
+	return writeError(w, r, http.StatusBadRequest, i18n.BadRequest)
 }
 
 // NotFound responds with code 404.
-func NotFound(w http.ResponseWriter) error {
-	w.WriteHeader(http.StatusNotFound)
-	return writeError(w, "not found")
+func NotFound(w http.ResponseWriter, r *http.Request) error {
+	return writeError(w, r, http.StatusNotFound, i18n.NotFound)
+}
+
+// NotAcceptable responds with code 406.
+func NotAcceptable(w http.ResponseWriter, r *http.Request) error {
+	return writeError(w, r, http.StatusNotAcceptable, i18n.NotAcceptable)
+}
+
+// UnsupportedMediaType responds with code 415.
+func UnsupportedMediaType(w http.ResponseWriter, r *http.Request) error {
+	return writeError(w, r, http.StatusUnsupportedMediaType, i18n.UnsupportedMediaType)
+}
+
+// TooManyRequests responds with code 429.
+func TooManyRequests(w http.ResponseWriter, r *http.Request) error {
+	return writeError(w, r, http.StatusTooManyRequests, i18n.TooManyRequests)
 }
 
 // InternalServerError with code 500.
-func InternalServerError(w http.ResponseWriter) error {
-	w.WriteHeader(http.StatusInternalServerError)
-	return writeError(w, "internal server error")
+func InternalServerError(w http.ResponseWriter, r *http.Request) error {
+	return writeError(w, r, http.StatusInternalServerError, i18n.InternalError)
 }
 
 // ValidationError responds with code 422.
-func ValidationError(w http.ResponseWriter, ers validation.Errors) error {
+func ValidationError(w http.ResponseWriter, r *http.Request, ers validation.Errors) error {
+	ers = localizeErrors(w, r, ers)
+
+	if acceptsProblem(r) {
+		return writeProblem(w, http.StatusUnprocessableEntity, ers.Message, ers.Details, ers.Codes)
+	}
+
+	w.Header().Set("Content-Type", MediaJSON)
 	w.WriteHeader(http.StatusUnprocessableEntity)
 
 	if err := json.NewEncoder(w).Encode(&ers); err != nil {
@@ -69,7 +93,19 @@ type messageResponse struct {
 	Message string `json:"message"`
 }
 
-func writeError(w http.ResponseWriter, message string) error {
+// writeError responds with problem details if the client accepts them,
+// otherwise with JSON regardless of Accept header so that clients
+// always can read the error.
+func writeError(w http.ResponseWriter, r *http.Request, code int, key string) error {
+	message := i18n.Translate(negotiateLanguage(w, r), key)
+
+	if acceptsProblem(r) {
+		return writeProblem(w, code, message, nil, nil)
+	}
+
+	w.Header().Set("Content-Type", MediaJSON)
+	w.WriteHeader(code)
+
 	resp := messageResponse{
 		Message: message,
 	}
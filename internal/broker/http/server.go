@@ -1,39 +1,207 @@
 package http
 
 import (
+	"context"
+	"net"
 	"net/http"
 	"time"
 
 	cardHandlers "github.com/dipress/cards/internal/broker/http/card"
 	"github.com/dipress/cards/internal/broker/http/handler"
-	"github.com/dipress/cards/internal/card"
+	"github.com/dipress/cards/internal/broker/http/health"
 	"github.com/dipress/cards/internal/kit/logger"
+	"github.com/dipress/cards/internal/kit/ratelimit"
 	"github.com/gorilla/mux"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
 )
 
 const (
-	timeout = 15 * time.Second
+	timeout     = 15 * time.Second
+	idleTimeout = 60 * time.Second
 )
 
 // Services contains all the services.
 type Services struct {
-	Card *card.Service
+	Card cardHandlers.Service
+}
+
+type options struct {
+	readTimeout  time.Duration
+	writeTimeout time.Duration
+	idleTimeout  time.Duration
+	baseContext  context.Context
+	readiness    *Readiness
+	healthChecks []health.Check
+	registry     *prometheus.Registry
+	rateLimiter  *rateLimiter
+	rateLimitKey KeyFunc
+	cors         *CORS
+	compressMin  int
+	requestCtx   func(context.Context) context.Context
+}
+
+// Option overrides behavior of the server.
+type Option func(*options)
+
+// WithTimeouts allows to set read, write and idle timeouts.
+func WithTimeouts(read, write, idle time.Duration) Option {
+	return func(o *options) {
+		o.readTimeout = read
+		o.writeTimeout = write
+		o.idleTimeout = idle
+	}
+}
+
+// WithBaseContext allows to set the parent context of all requests,
+// cancelling it cancels requests which are still in flight.
+func WithBaseContext(ctx context.Context) Option {
+	return func(o *options) {
+		o.baseContext = ctx
+	}
+}
+
+// WithReadiness allows to report readiness of the server on /readyz.
+func WithReadiness(r *Readiness) Option {
+	return func(o *options) {
+		o.readiness = r
+	}
+}
+
+// WithHealthCheck adds a dependency check to /readyz.
+func WithHealthCheck(name string, c health.Checker) Option {
+	return func(o *options) {
+		o.healthChecks = append(o.healthChecks, health.Check{
+			Name:    name,
+			Checker: c,
+		})
+	}
+}
+
+// WithMetrics allows to collect http metrics into the registry
+// and to expose everything it gathers on /metrics.
+func WithMetrics(reg *prometheus.Registry) Option {
+	return func(o *options) {
+		o.registry = reg
+	}
+}
+
+// WithRateLimit allows to limit requests of every authenticated user
+// or client ip, reads and writes have separate budgets.
+func WithRateLimit(store ratelimit.Store, read, write ratelimit.Limit) Option {
+	return func(o *options) {
+		o.rateLimiter = &rateLimiter{
+			store: store,
+			read:  read,
+			write: write,
+			key:   UserKey,
+		}
+	}
+}
+
+// WithRateLimitKey allows to key rate limits by fn instead of UserKey.
+func WithRateLimitKey(fn KeyFunc) Option {
+	return func(o *options) {
+		o.rateLimitKey = fn
+	}
+}
+
+// WithCORS allows browser clients from other origins
+// to call the API.
+func WithCORS(c CORS) Option {
+	return func(o *options) {
+		o.cors = &c
+	}
+}
+
+// WithCompression allows to compress responses which
+// are at least minSize bytes long with gzip or brotli.
+func WithCompression(minSize int) Option {
+	return func(o *options) {
+		o.compressMin = minSize
+	}
+}
+
+// WithRequestContext allows to derive the context
+// of every API request with fn.
+func WithRequestContext(fn func(context.Context) context.Context) Option {
+	return func(o *options) {
+		o.requestCtx = fn
+	}
 }
 
 // NewServer prepares the http server to work.
-func NewServer(addr string, logger *logger.Logger, services *Services) *http.Server {
+func NewServer(addr string, logger *logger.Logger, services *Services, opts ...Option) *http.Server {
+	o := options{
+		readTimeout:  timeout,
+		writeTimeout: timeout,
+		idleTimeout:  idleTimeout,
+	}
+
+	for _, opt := range opts {
+		opt(&o)
+	}
+
 	mux := mux.NewRouter().StrictSlash(true)
 
-	base := handler.NewChain(contentTypeMiddleware)
+	base := handler.NewChain(
+		requestIDMiddleware,
+		tracingMiddleware,
+		accessLogMiddleware(logger),
+	)
+
+	checks := o.healthChecks
+	if o.readiness != nil {
+		checks = append([]health.Check{{Name: "server", Checker: readinessChecker(o.readiness)}}, checks...)
+	}
+	health.Prepare(mux, checks, logger)
+
+	if o.registry != nil {
+		base = base.Append(metricsMiddleware(newHTTPMetrics(o.registry)))
+		mux.Handle("/metrics", promhttp.HandlerFor(o.registry, promhttp.HandlerOpts{})).Methods(http.MethodGet)
+	}
+
+	// Compression goes after logging and metrics
+	// so that they count bytes sent over the wire.
+	if o.compressMin > 0 {
+		base = base.Append(compressMiddleware(o.compressMin))
+	}
+
+	if o.rateLimiter != nil {
+		if o.rateLimitKey != nil {
+			o.rateLimiter.key = o.rateLimitKey
+		}
+		base = base.Append(rateLimitMiddleware(logger, o.rateLimiter))
+	}
+
+	// Panics are recovered inside of logging, tracing
+	// and metrics so they see the 500 response.
+	base = base.Append(recoverMiddleware(logger))
+
+	if o.requestCtx != nil {
+		base = base.Append(contextMiddleware(o.requestCtx))
+	}
 
 	cards := mux.PathPrefix("/api/v1/cards").Subrouter()
 	cardHandlers.Prepare(cards, services.Card, finalizeMiddleware(logger, base))
 
+	var h http.Handler = mux
+	if o.cors != nil {
+		h = newCORS(o.cors).handler(h)
+	}
+
 	s := http.Server{
 		Addr:         addr,
-		Handler:      mux,
-		ReadTimeout:  timeout,
-		WriteTimeout: timeout,
+		Handler:      h,
+		ReadTimeout:  o.readTimeout,
+		WriteTimeout: o.writeTimeout,
+		IdleTimeout:  o.idleTimeout,
+	}
+
+	if o.baseContext != nil {
+		s.BaseContext = func(net.Listener) context.Context {
+			return o.baseContext
+		}
 	}
 
 	return &s
@@ -0,0 +1,99 @@
+package http
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/dipress/cards/internal/kit/logger"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+func testLogger(t *testing.T) *logger.Logger {
+	l, err := logger.New(logger.SetOutput(ioutil.Discard))
+	if err != nil {
+		t.Fatalf("failed to create logger: %v", err)
+	}
+
+	return l
+}
+
+func TestNewServerHealth(t *testing.T) {
+	var readiness Readiness
+
+	srv := NewServer("", testLogger(t), &Services{}, WithReadiness(&readiness))
+
+	tests := []struct {
+		name  string
+		path  string
+		ready bool
+		code  int
+	}{
+		{
+			name: "live while not ready",
+			path: "/healthz",
+			code: http.StatusOK,
+		},
+		{
+			name: "not ready",
+			path: "/readyz",
+			code: http.StatusServiceUnavailable,
+		},
+		{
+			name:  "ready",
+			path:  "/readyz",
+			ready: true,
+			code:  http.StatusOK,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			readiness.SetReady(tc.ready)
+
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest(http.MethodGet, tc.path, nil)
+
+			srv.Handler.ServeHTTP(w, r)
+
+			if w.Code != tc.code {
+				t.Errorf("unexpected code: %d expected %d", w.Code, tc.code)
+			}
+
+			if ct := w.Header().Get("Content-Type"); ct != "application/json" {
+				t.Errorf("unexpected Content-Type header: %s", ct)
+			}
+		})
+	}
+}
+
+func TestNewServerMetrics(t *testing.T) {
+	reg := prometheus.NewRegistry()
+	srv := NewServer("", testLogger(t), &Services{}, WithMetrics(reg))
+
+	// Health checks bypass the middleware and aren't counted.
+	for _, path := range []string{"/healthz", "/api/v1/cards/abc"} {
+		w := httptest.NewRecorder()
+		srv.Handler.ServeHTTP(w, httptest.NewRequest(http.MethodGet, path, nil))
+	}
+
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest(http.MethodGet, "/metrics", nil)
+
+	srv.Handler.ServeHTTP(w, r)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("unexpected code: %d expected %d", w.Code, http.StatusOK)
+	}
+
+	expectedBody := `cards_http_requests_total{method="GET",route="/api/v1/cards/{id}",status="400"} 1`
+	if !strings.Contains(w.Body.String(), expectedBody) {
+		t.Errorf("unexpected body:\n\t\t%s\nexpected:\n\t\t%s", w.Body.String(), expectedBody)
+	}
+
+	if strings.Contains(w.Body.String(), `route="/healthz"`) {
+		t.Errorf("unexpected health check metrics:\n\t\t%s", w.Body.String())
+	}
+}
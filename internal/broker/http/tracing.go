@@ -0,0 +1,45 @@
+package http
+
+import (
+	"net/http"
+
+	"github.com/dipress/cards/internal/broker/http/handler"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+)
+
+var tracer = otel.Tracer("github.com/dipress/cards/internal/broker/http")
+
+// tracingMiddleware starts a server span for the request
+// continuing the trace from the W3C traceparent header.
+func tracingMiddleware(next handler.Handler) handler.Handler {
+	h := handler.Func(func(w http.ResponseWriter, r *http.Request) error {
+		ctx := otel.GetTextMapPropagator().Extract(r.Context(), propagation.HeaderCarrier(r.Header))
+		route := routeTemplate(r)
+
+		ctx, span := tracer.Start(ctx, r.Method+" "+route,
+			trace.WithSpanKind(trace.SpanKindServer),
+			trace.WithAttributes(
+				attribute.String("http.request.method", r.Method),
+				attribute.String("http.route", route),
+				attribute.String("url.path", r.URL.Path),
+			),
+		)
+		defer span.End()
+
+		sw := newStatusWriter(w)
+		err := next.Handle(sw, r.WithContext(ctx))
+
+		span.SetAttributes(attribute.Int("http.response.status_code", sw.Status()))
+		if sw.Status() >= http.StatusInternalServerError {
+			span.SetStatus(codes.Error, http.StatusText(sw.Status()))
+		}
+
+		return err
+	})
+
+	return h
+}
@@ -0,0 +1,17 @@
+package http
+
+import "context"
+
+type userKey struct{}
+
+// WithUser returns the context of a request authenticated
+// as the user, authentication middlewares set it.
+func WithUser(ctx context.Context, id int) context.Context {
+	return context.WithValue(ctx, userKey{}, id)
+}
+
+// User returns id of the authenticated user of the request.
+func User(ctx context.Context) (int, bool) {
+	id, ok := ctx.Value(userKey{}).(int)
+	return id, ok
+}
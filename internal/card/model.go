@@ -12,13 +12,16 @@ var ErrNotFound = errors.New("card not found")
 
 // constains all card fields.
 type Card struct {
-	ID            int       `json:"id"`
-	UserID        int       `json:"user_id"`
-	Word          string    `json:"word"`
-	Transcription string    `json:"transcription"`
-	Translation   string    `json:"translation"`
-	CreatedAt     time.Time `json:"created_at"`
-	UpdatedAt     time.Time `json:"updated_at"`
+	ID             int        `json:"id"`
+	UserID         int        `json:"user_id"`
+	Word           string     `json:"word"`
+	Transcription  string     `json:"transcription"`
+	Translation    string     `json:"translation"`
+	CorrectAnswers int        `json:"correct_answers"`
+	WrongAnswers   int        `json:"wrong_answers"`
+	AnsweredAt     *time.Time `json:"answered_at"`
+	CreatedAt      time.Time  `json:"created_at"`
+	UpdatedAt      time.Time  `json:"updated_at"`
 }
 
 // NewCard contains the information which needs to create a new Card.
@@ -37,6 +40,16 @@ type Form struct {
 	Translation   string `json:"translation"`
 }
 
+// ImportForm is a form of cards created at once.
+type ImportForm struct {
+	Cards []Form `json:"cards"`
+}
+
+// AnswerForm is a form of the learner's answer on a card.
+type AnswerForm struct {
+	Correct bool `json:"correct"`
+}
+
 // Cards contains slice of the cards.
 type Cards struct {
 	Cards []Card `json:"cards"`
@@ -3,15 +3,20 @@ package card
 import (
 	"context"
 	"fmt"
+	"sync"
 )
 
 // go:generate mockgen -source=service.go -package=card -destination=service.mock.go
 
 // Repository allows to work with the database.
+// Update fills the card with the stored row, Update and Delete
+// return ErrNotFound when there is no card with the id.
 type Repository interface {
 	Create(context.Context, *NewCard, *Card) error
 	Find(context.Context, int) (*Card, error)
+	List(context.Context, int) ([]Card, error)
 	Update(context.Context, int, *Card) error
+	Answer(context.Context, int, bool) (*Card, error)
 	Delete(context.Context, int) error
 }
 
@@ -20,17 +25,97 @@ type Validater interface {
 	Validate(context.Context, *Form) error
 }
 
+// Transactor runs fn atomically, repositories called
+// with the context passed to fn join the transaction.
+type Transactor interface {
+	WithinTransaction(ctx context.Context, fn func(ctx context.Context) error) error
+}
+
+type txKey struct{}
+
+// transaction keeps hooks which wait for the commit.
+type transaction struct {
+	mu          sync.Mutex
+	afterCommit []func()
+}
+
+// ContextWithTransaction marks ctx as the context of a running
+// transaction, transactors pass such a context to fn and call
+// committed once the transaction commits.
+func ContextWithTransaction(ctx context.Context) (_ context.Context, committed func()) {
+	tx := &transaction{}
+
+	committed = func() {
+		tx.mu.Lock()
+		hooks := tx.afterCommit
+		tx.afterCommit = nil
+		tx.mu.Unlock()
+
+		for _, fn := range hooks {
+			fn()
+		}
+	}
+
+	return context.WithValue(ctx, txKey{}, tx), committed
+}
+
+// InTransaction tells whether ctx belongs to a transaction,
+// its changes aren't visible to others until the commit.
+func InTransaction(ctx context.Context) bool {
+	_, ok := ctx.Value(txKey{}).(*transaction)
+	return ok
+}
+
+// AfterCommit runs fn once the transaction of ctx commits, a rolled
+// back transaction drops it. fn runs right away without a transaction.
+func AfterCommit(ctx context.Context, fn func()) {
+	tx, ok := ctx.Value(txKey{}).(*transaction)
+	if !ok {
+		fn()
+		return
+	}
+
+	tx.mu.Lock()
+	tx.afterCommit = append(tx.afterCommit, fn)
+	tx.mu.Unlock()
+}
+
+// nopTransactor is used for repositories without transactions.
+type nopTransactor struct{}
+
+func (nopTransactor) WithinTransaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	return fn(ctx)
+}
+
+// ServiceOption overrides behavior of Service.
+type ServiceOption func(*Service)
+
+// WithTransactor makes the service run multi-step
+// operations in transactions of t.
+func WithTransactor(t Transactor) ServiceOption {
+	return func(s *Service) {
+		s.transactor = t
+	}
+}
+
 // Service is a use case for card creation.
 type Service struct {
 	Repository
 	Validater
+
+	transactor Transactor
 }
 
 // NewService factory prepares service for all futher operations.
-func NewService(r Repository, v Validater) *Service {
+func NewService(r Repository, v Validater, opts ...ServiceOption) *Service {
 	s := Service{
 		Repository: r,
 		Validater:  v,
+		transactor: nopTransactor{},
+	}
+
+	for _, opt := range opts {
+		opt(&s)
 	}
 
 	return &s
@@ -57,6 +142,52 @@ func (s *Service) Create(ctx context.Context, f *Form) (*Card, error) {
 
 }
 
+// MaxImport is the largest number of cards imported at once,
+// all of them are created in a single transaction.
+const MaxImport = 1000
+
+// Import creates cards of all the forms atomically, no card is created
+// if any of them fails. Without a transactor the cards created before
+// the failure stay.
+func (s *Service) Import(ctx context.Context, forms []Form) (*Cards, error) {
+	for i := range forms {
+		if err := s.Validater.Validate(ctx, &forms[i]); err != nil {
+			return nil, fmt.Errorf("validater validate row %d: %w", i+1, err)
+		}
+	}
+
+	cards := make([]Card, 0, len(forms))
+
+	err := s.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		for i, f := range forms {
+			nc := NewCard{
+				Word:          f.Word,
+				Transcription: f.Transcription,
+				Translation:   f.Translation,
+				UserID:        f.UserID,
+			}
+
+			var card Card
+			if err := s.Repository.Create(ctx, &nc, &card); err != nil {
+				return fmt.Errorf("repository create row %d: %w", i+1, err)
+			}
+
+			cards = append(cards, card)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("within transaction: %w", err)
+	}
+
+	cs := Cards{
+		Cards: cards,
+	}
+
+	return &cs, nil
+}
+
 // Find finds a card.
 func (s *Service) Find(ctx context.Context, id int) (*Card, error) {
 	c, err := s.Repository.Find(ctx, id)
@@ -67,37 +198,53 @@ func (s *Service) Find(ctx context.Context, id int) (*Card, error) {
 	return c, nil
 }
 
+// List lists cards of the user.
+func (s *Service) List(ctx context.Context, userID int) (*Cards, error) {
+	cards, err := s.Repository.List(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("repository list: %w", err)
+	}
+
+	cs := Cards{
+		Cards: cards,
+	}
+
+	return &cs, nil
+}
+
 // Update updates a card.
 func (s *Service) Update(ctx context.Context, id int, f *Form) (*Card, error) {
 	if err := s.Validater.Validate(ctx, f); err != nil {
 		return nil, fmt.Errorf("validater validate: %w", err)
 	}
 
-	c, err := s.Repository.Find(ctx, id)
-	if err != nil {
-		return nil, fmt.Errorf("repository find: %w", err)
+	c := Card{
+		UserID:        f.UserID,
+		Word:          f.Word,
+		Transcription: f.Transcription,
+		Translation:   f.Translation,
 	}
 
-	c.UserID = f.UserID
-	c.Word = f.Word
-	c.Transcription = f.Transcription
-	c.Translation = f.Translation
-
-	if err := s.Repository.Update(ctx, id, c); err != nil {
+	if err := s.Repository.Update(ctx, id, &c); err != nil {
 		return nil, fmt.Errorf("repository update: %w", err)
 	}
 
-	return c, err
+	return &c, nil
 }
 
-// Delete deletes a card.
-func (s *Service) Delete(ctx context.Context, id int) error {
-	c, err := s.Repository.Find(ctx, id)
+// Answer records the learner's answer on a card.
+func (s *Service) Answer(ctx context.Context, id int, f *AnswerForm) (*Card, error) {
+	c, err := s.Repository.Answer(ctx, id, f.Correct)
 	if err != nil {
-		return fmt.Errorf("repository find: %w", err)
+		return nil, fmt.Errorf("repository answer: %w", err)
 	}
 
-	if err := s.Repository.Delete(ctx, c.ID); err != nil {
+	return c, nil
+}
+
+// Delete deletes a card.
+func (s *Service) Delete(ctx context.Context, id int) error {
+	if err := s.Repository.Delete(ctx, id); err != nil {
 		return fmt.Errorf("repository delete: %w", err)
 	}
 
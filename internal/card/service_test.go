@@ -130,6 +130,54 @@ func Test_Find_Service(t *testing.T) {
 	}
 }
 
+func Test_List_Service(t *testing.T) {
+	tests := []struct {
+		name           string
+		repositoryFunc func(m *MockRepository)
+		wantErr        bool
+	}{
+		{
+			name: "ok",
+			repositoryFunc: func(m *MockRepository) {
+				m.EXPECT().List(gomock.Any(), gomock.Any()).Return([]Card{{}}, nil)
+			},
+		},
+		{
+			name: "internal error",
+			repositoryFunc: func(m *MockRepository) {
+				m.EXPECT().List(gomock.Any(), gomock.Any()).Return(nil, errors.New("mock error"))
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+
+			repo := NewMockRepository(ctrl)
+			tc.repositoryFunc(repo)
+
+			s := NewService(repo, nil)
+
+			_, err := s.List(ctx, 1)
+			if tc.wantErr {
+				assert.Error(t, err)
+				return
+			}
+
+			assert.Nil(t, err)
+		})
+	}
+}
+
 func Test_Update_Service(t *testing.T) {
 	tests := []struct {
 		name           string
@@ -143,7 +191,6 @@ func Test_Update_Service(t *testing.T) {
 				m.EXPECT().Validate(gomock.Any(), gomock.Any()).Return(nil)
 			},
 			repositoryFunc: func(m *MockRepository) {
-				m.EXPECT().Find(gomock.Any(), gomock.Any()).Return(&Card{}, nil)
 				m.EXPECT().Update(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil)
 			},
 		},
@@ -156,12 +203,12 @@ func Test_Update_Service(t *testing.T) {
 			wantErr:        true,
 		},
 		{
-			name: "find card error",
+			name: "card not found",
 			validaterFunc: func(m *MockValidater) {
 				m.EXPECT().Validate(gomock.Any(), gomock.Any()).Return(nil)
 			},
 			repositoryFunc: func(m *MockRepository) {
-				m.EXPECT().Find(gomock.Any(), gomock.Any()).Return(&Card{}, errors.New("mock error"))
+				m.EXPECT().Update(gomock.Any(), 1, gomock.Any()).Return(ErrNotFound)
 			},
 			wantErr: true,
 		},
@@ -171,7 +218,6 @@ func Test_Update_Service(t *testing.T) {
 				m.EXPECT().Validate(gomock.Any(), gomock.Any()).Return(nil)
 			},
 			repositoryFunc: func(m *MockRepository) {
-				m.EXPECT().Find(gomock.Any(), gomock.Any()).Return(&Card{}, nil)
 				m.EXPECT().Update(gomock.Any(), gomock.Any(), gomock.Any()).Return(errors.New("mock error"))
 			},
 			wantErr: true,
@@ -216,6 +262,143 @@ func Test_Update_Service(t *testing.T) {
 	}
 }
 
+func Test_Import_Service(t *testing.T) {
+	inTransaction := func(m *MockTransactor) {
+		m.EXPECT().WithinTransaction(gomock.Any(), gomock.Any()).DoAndReturn(
+			func(ctx context.Context, fn func(context.Context) error) error {
+				return fn(ctx)
+			},
+		)
+	}
+
+	tests := []struct {
+		name           string
+		validaterFunc  func(mock *MockValidater)
+		repositoryFunc func(mock *MockRepository)
+		transactorFunc func(mock *MockTransactor)
+		wantErr        bool
+	}{
+		{
+			name: "ok",
+			validaterFunc: func(m *MockValidater) {
+				m.EXPECT().Validate(gomock.Any(), gomock.Any()).Return(nil).Times(2)
+			},
+			repositoryFunc: func(m *MockRepository) {
+				m.EXPECT().Create(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil).Times(2)
+			},
+			transactorFunc: inTransaction,
+		},
+		{
+			name: "validation error",
+			validaterFunc: func(m *MockValidater) {
+				m.EXPECT().Validate(gomock.Any(), gomock.Any()).Return(nil)
+				m.EXPECT().Validate(gomock.Any(), gomock.Any()).Return(errors.New("mock error"))
+			},
+			repositoryFunc: func(m *MockRepository) {},
+			transactorFunc: func(m *MockTransactor) {},
+			wantErr:        true,
+		},
+		{
+			name: "create card error",
+			validaterFunc: func(m *MockValidater) {
+				m.EXPECT().Validate(gomock.Any(), gomock.Any()).Return(nil).Times(2)
+			},
+			repositoryFunc: func(m *MockRepository) {
+				m.EXPECT().Create(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil)
+				m.EXPECT().Create(gomock.Any(), gomock.Any(), gomock.Any()).Return(errors.New("mock error"))
+			},
+			transactorFunc: inTransaction,
+			wantErr:        true,
+		},
+	}
+
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			repo := NewMockRepository(ctrl)
+			validater := NewMockValidater(ctrl)
+			transactor := NewMockTransactor(ctrl)
+
+			tc.repositoryFunc(repo)
+			tc.validaterFunc(validater)
+			tc.transactorFunc(transactor)
+
+			s := NewService(repo, validater, WithTransactor(transactor))
+
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+
+			forms := []Form{
+				{Word: "do", Transcription: "do͞o", Translation: "делать", UserID: 1},
+				{Word: "make", Transcription: "māk", Translation: "сделать", UserID: 1},
+			}
+
+			cards, err := s.Import(ctx, forms)
+
+			if tc.wantErr {
+				assert.Error(t, err)
+				return
+			}
+
+			assert.Nil(t, err)
+			assert.Len(t, cards.Cards, 2)
+		})
+	}
+}
+
+func Test_Answer_Service(t *testing.T) {
+	tests := []struct {
+		name           string
+		repositoryFunc func(m *MockRepository)
+		wantErr        bool
+	}{
+		{
+			name: "ok",
+			repositoryFunc: func(m *MockRepository) {
+				m.EXPECT().Answer(gomock.Any(), 1, true).Return(&Card{}, nil)
+			},
+		},
+		{
+			name: "answer card error",
+			repositoryFunc: func(m *MockRepository) {
+				m.EXPECT().Answer(gomock.Any(), 1, true).Return(nil, errors.New("mock error"))
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			repo := NewMockRepository(ctrl)
+			tc.repositoryFunc(repo)
+
+			s := NewService(repo, nil)
+
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+
+			_, err := s.Answer(ctx, 1, &AnswerForm{Correct: true})
+			if tc.wantErr {
+				assert.Error(t, err)
+				return
+			}
+
+			assert.Nil(t, err)
+		})
+	}
+}
+
 func Test_Delete_Service(t *testing.T) {
 	tests := []struct {
 		name           string
@@ -225,21 +408,19 @@ func Test_Delete_Service(t *testing.T) {
 		{
 			name: "ok",
 			repositoryFunc: func(m *MockRepository) {
-				m.EXPECT().Find(gomock.Any(), gomock.Any()).Return(&Card{}, nil)
 				m.EXPECT().Delete(gomock.Any(), gomock.Any()).Return(nil)
 			},
 		},
 		{
-			name: "find card error",
+			name: "card not found",
 			repositoryFunc: func(m *MockRepository) {
-				m.EXPECT().Find(gomock.Any(), gomock.Any()).Return(&Card{}, errors.New("mock error"))
+				m.EXPECT().Delete(gomock.Any(), 1).Return(ErrNotFound)
 			},
 			wantErr: true,
 		},
 		{
 			name: "delete card error",
 			repositoryFunc: func(m *MockRepository) {
-				m.EXPECT().Find(gomock.Any(), gomock.Any()).Return(&Card{}, nil)
 				m.EXPECT().Delete(gomock.Any(), gomock.Any()).Return(errors.New("mock error"))
 			},
 			wantErr: true,
@@ -274,3 +455,37 @@ func Test_Delete_Service(t *testing.T) {
 		})
 	}
 }
+
+func Test_AfterCommit(t *testing.T) {
+	t.Log("without transaction")
+	{
+		var ran bool
+		AfterCommit(context.Background(), func() { ran = true })
+
+		t.Log("\ttest:0\tshould run right away")
+		if !ran {
+			t.Error("hook didn't run")
+		}
+	}
+
+	t.Log("with transaction")
+	{
+		ctx, committed := ContextWithTransaction(context.Background())
+
+		var ran int
+		AfterCommit(ctx, func() { ran++ })
+
+		t.Log("\ttest:0\tshould wait for the commit")
+		if ran != 0 {
+			t.Error("hook ran before the commit")
+		}
+
+		committed()
+		committed()
+
+		t.Log("\ttest:1\tshould run once after the commit")
+		if ran != 1 {
+			t.Errorf("unexpected runs: %d expected 1", ran)
+		}
+	}
+}
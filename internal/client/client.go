@@ -0,0 +1,231 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/dipress/cards/internal/card"
+	"github.com/dipress/cards/internal/validation"
+)
+
+const (
+	cardsPath      = "/api/v1/cards"
+	defaultTimeout = 15 * time.Second
+)
+
+// Error raises when the server responds with an unexpected status code.
+type Error struct {
+	StatusCode int
+	Message    string
+}
+
+// Error implements error interface.
+func (e *Error) Error() string {
+	return fmt.Sprintf("unexpected status code %d: %s", e.StatusCode, e.Message)
+}
+
+// Client allows to work with the cards HTTP API.
+type Client struct {
+	baseURL    *url.URL
+	httpClient *http.Client
+}
+
+// Option overrides behavior of Client.
+type Option func(*Client) error
+
+// WithHTTPClient allows to set http client
+// which will be used for requests.
+func WithHTTPClient(hc *http.Client) Option {
+	return func(c *Client) error {
+		c.httpClient = hc
+		return nil
+	}
+}
+
+// New prepares the client to work with the server at given base url.
+func New(baseURL string, options ...Option) (*Client, error) {
+	u, err := url.Parse(strings.TrimRight(baseURL, "/"))
+	if err != nil {
+		return nil, fmt.Errorf("parse base url: %w", err)
+	}
+
+	c := Client{
+		baseURL: u,
+		httpClient: &http.Client{
+			Timeout: defaultTimeout,
+		},
+	}
+
+	for _, option := range options {
+		if err := option(&c); err != nil {
+			return nil, fmt.Errorf("apply option: %w", err)
+		}
+	}
+
+	return &c, nil
+}
+
+// Create creates a card.
+func (c *Client) Create(ctx context.Context, f *card.Form) (*card.Card, error) {
+	var cd card.Card
+	if err := c.do(ctx, http.MethodPost, cardsPath, nil, f, &cd); err != nil {
+		return nil, fmt.Errorf("create: %w", err)
+	}
+
+	return &cd, nil
+}
+
+// Import creates all the cards or none of them.
+func (c *Client) Import(ctx context.Context, forms []card.Form) (*card.Cards, error) {
+	f := card.ImportForm{
+		Cards: forms,
+	}
+
+	var cs card.Cards
+	if err := c.do(ctx, http.MethodPost, cardsPath+"/import", nil, &f, &cs); err != nil {
+		return nil, fmt.Errorf("import: %w", err)
+	}
+
+	return &cs, nil
+}
+
+// Find finds a card by id.
+func (c *Client) Find(ctx context.Context, id int) (*card.Card, error) {
+	var cd card.Card
+	if err := c.do(ctx, http.MethodGet, cardPath(id), nil, nil, &cd); err != nil {
+		return nil, fmt.Errorf("find: %w", err)
+	}
+
+	return &cd, nil
+}
+
+// List lists cards of the user.
+func (c *Client) List(ctx context.Context, userID int) (*card.Cards, error) {
+	query := url.Values{}
+	query.Set("user_id", strconv.Itoa(userID))
+
+	var cs card.Cards
+	if err := c.do(ctx, http.MethodGet, cardsPath, query, nil, &cs); err != nil {
+		return nil, fmt.Errorf("list: %w", err)
+	}
+
+	return &cs, nil
+}
+
+// Update updates a card by id.
+func (c *Client) Update(ctx context.Context, id int, f *card.Form) (*card.Card, error) {
+	var cd card.Card
+	if err := c.do(ctx, http.MethodPut, cardPath(id), nil, f, &cd); err != nil {
+		return nil, fmt.Errorf("update: %w", err)
+	}
+
+	return &cd, nil
+}
+
+// Answer records the learner's answer on a card by id.
+func (c *Client) Answer(ctx context.Context, id int, correct bool) (*card.Card, error) {
+	f := card.AnswerForm{
+		Correct: correct,
+	}
+
+	var cd card.Card
+	if err := c.do(ctx, http.MethodPost, cardPath(id)+"/answers", nil, &f, &cd); err != nil {
+		return nil, fmt.Errorf("answer: %w", err)
+	}
+
+	return &cd, nil
+}
+
+// Delete deletes a card by id.
+func (c *Client) Delete(ctx context.Context, id int) error {
+	if err := c.do(ctx, http.MethodDelete, cardPath(id), nil, nil, nil); err != nil {
+		return fmt.Errorf("delete: %w", err)
+	}
+
+	return nil
+}
+
+func cardPath(id int) string {
+	return fmt.Sprintf("%s/%d", cardsPath, id)
+}
+
+func (c *Client) do(ctx context.Context, method, path string, query url.Values, in, out interface{}) error {
+	u := *c.baseURL
+	u.Path += path
+	u.RawQuery = query.Encode()
+
+	var body io.Reader
+	if in != nil {
+		var buf bytes.Buffer
+		if err := json.NewEncoder(&buf).Encode(in); err != nil {
+			return fmt.Errorf("encode: %w", err)
+		}
+		body = &buf
+	}
+
+	req, err := http.NewRequest(method, u.String(), body)
+	if err != nil {
+		return fmt.Errorf("new request: %w", err)
+	}
+	req = req.WithContext(ctx)
+
+	req.Header.Set("Accept", "application/json")
+	if in != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("do request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return decodeError(resp)
+	}
+
+	if out == nil {
+		return nil
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("decode: %w", err)
+	}
+
+	return nil
+}
+
+type messageResponse struct {
+	Message string `json:"message"`
+}
+
+func decodeError(resp *http.Response) error {
+	switch resp.StatusCode {
+	case http.StatusNotFound:
+		return card.ErrNotFound
+	case http.StatusUnprocessableEntity:
+		var ves validation.Errors
+		if err := json.NewDecoder(resp.Body).Decode(&ves); err != nil {
+			return fmt.Errorf("decode validation errors: %w", err)
+		}
+		return ves
+	}
+
+	var msg messageResponse
+	if err := json.NewDecoder(resp.Body).Decode(&msg); err != nil {
+		msg.Message = http.StatusText(resp.StatusCode)
+	}
+
+	return &Error{
+		StatusCode: resp.StatusCode,
+		Message:    msg.Message,
+	}
+}
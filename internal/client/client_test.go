@@ -0,0 +1,222 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"io/ioutil"
+	"net/http/httptest"
+	"testing"
+
+	httpBroker "github.com/dipress/cards/internal/broker/http"
+	"github.com/dipress/cards/internal/card"
+	"github.com/dipress/cards/internal/kit/logger"
+	"github.com/dipress/cards/internal/validation"
+	gomock "github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+func setupClient(t *testing.T, repositoryFunc func(m *card.MockRepository)) (*Client, func()) {
+	ctrl := gomock.NewController(t)
+
+	repo := card.NewMockRepository(ctrl)
+	repositoryFunc(repo)
+
+	l, err := logger.New(logger.SetOutput(ioutil.Discard))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	services := httpBroker.Services{
+		Card: card.NewService(repo, &validation.Card{}),
+	}
+
+	srv := httptest.NewServer(httpBroker.NewServer("", l, &services).Handler)
+
+	c, err := New(srv.URL)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	teardown := func() {
+		srv.Close()
+		ctrl.Finish()
+	}
+
+	return c, teardown
+}
+
+func TestCreate(t *testing.T) {
+	tests := []struct {
+		name           string
+		form           card.Form
+		repositoryFunc func(m *card.MockRepository)
+		wantErr        bool
+	}{
+		{
+			name: "ok",
+			form: card.Form{
+				Word:          "do",
+				Transcription: "do͞o",
+				Translation:   "делать",
+				UserID:        1,
+			},
+			repositoryFunc: func(m *card.MockRepository) {
+				m.EXPECT().Create(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil)
+			},
+		},
+		{
+			name: "validation error",
+			form: card.Form{
+				Word:   "do",
+				UserID: 1,
+			},
+			repositoryFunc: func(m *card.MockRepository) {},
+			wantErr:        true,
+		},
+	}
+
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			c, teardown := setupClient(t, tc.repositoryFunc)
+			defer teardown()
+
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+
+			_, err := c.Create(ctx, &tc.form)
+			if tc.wantErr {
+				var ves validation.Errors
+				assert.True(t, errors.As(err, &ves))
+				return
+			}
+
+			assert.Nil(t, err)
+		})
+	}
+}
+
+func TestImport(t *testing.T) {
+	tests := []struct {
+		name           string
+		forms          []card.Form
+		repositoryFunc func(m *card.MockRepository)
+		wantErr        bool
+	}{
+		{
+			name: "ok",
+			forms: []card.Form{
+				{Word: "do", Transcription: "do͞o", Translation: "делать", UserID: 1},
+				{Word: "own", Transcription: "ōn", Translation: "владеть", UserID: 1},
+			},
+			repositoryFunc: func(m *card.MockRepository) {
+				m.EXPECT().Create(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil).Times(2)
+			},
+		},
+		{
+			name: "validation error",
+			forms: []card.Form{
+				{Word: "do", Transcription: "do͞o", Translation: "делать", UserID: 1},
+				{Word: "own", UserID: 1},
+			},
+			repositoryFunc: func(m *card.MockRepository) {},
+			wantErr:        true,
+		},
+	}
+
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			c, teardown := setupClient(t, tc.repositoryFunc)
+			defer teardown()
+
+			cs, err := c.Import(context.Background(), tc.forms)
+			if tc.wantErr {
+				var ves validation.Errors
+				assert.True(t, errors.As(err, &ves))
+				return
+			}
+
+			assert.Nil(t, err)
+			assert.Len(t, cs.Cards, len(tc.forms))
+		})
+	}
+}
+
+func TestFind(t *testing.T) {
+	tests := []struct {
+		name           string
+		repositoryFunc func(m *card.MockRepository)
+		expectErr      error
+	}{
+		{
+			name: "ok",
+			repositoryFunc: func(m *card.MockRepository) {
+				m.EXPECT().Find(gomock.Any(), 1).Return(&card.Card{ID: 1}, nil)
+			},
+		},
+		{
+			name: "not found error",
+			repositoryFunc: func(m *card.MockRepository) {
+				m.EXPECT().Find(gomock.Any(), 1).Return(nil, card.ErrNotFound)
+			},
+			expectErr: card.ErrNotFound,
+		},
+	}
+
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			c, teardown := setupClient(t, tc.repositoryFunc)
+			defer teardown()
+
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+
+			cd, err := c.Find(ctx, 1)
+			if tc.expectErr != nil {
+				assert.True(t, errors.Is(err, tc.expectErr))
+				return
+			}
+
+			assert.Nil(t, err)
+			assert.Equal(t, 1, cd.ID)
+		})
+	}
+}
+
+func TestList(t *testing.T) {
+	t.Parallel()
+
+	c, teardown := setupClient(t, func(m *card.MockRepository) {
+		m.EXPECT().List(gomock.Any(), 3).Return([]card.Card{{ID: 1}, {ID: 2}}, nil)
+	})
+	defer teardown()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	cs, err := c.List(ctx, 3)
+	assert.Nil(t, err)
+	assert.Len(t, cs.Cards, 2)
+}
+
+func TestDelete(t *testing.T) {
+	t.Parallel()
+
+	c, teardown := setupClient(t, func(m *card.MockRepository) {
+		m.EXPECT().Delete(gomock.Any(), 1).Return(nil)
+	})
+	defer teardown()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	assert.Nil(t, c.Delete(ctx, 1))
+}
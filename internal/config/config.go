@@ -0,0 +1,530 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/BurntSushi/toml"
+	"github.com/sirupsen/logrus"
+	yaml "gopkg.in/yaml.v2"
+)
+
+const (
+	envPrefix = "CARDS_"
+	filtered  = "[FILTERED]"
+
+	// FormatJSON is a json log format.
+	FormatJSON = "json"
+	// FormatText is a human readable log format.
+	FormatText = "text"
+
+	// ExporterNone disables tracing.
+	ExporterNone = "none"
+	// ExporterStdout writes spans to stdout.
+	ExporterStdout = "stdout"
+	// ExporterFile writes spans to a file.
+	ExporterFile = "file"
+	// ExporterOTLP sends spans to a collector over OTLP/HTTP.
+	ExporterOTLP = "otlp"
+)
+
+// Config holds the server configuration.
+type Config struct {
+	Server      Server      `yaml:"server" toml:"server"`
+	Database    Database    `yaml:"database" toml:"database"`
+	Log         Log         `yaml:"log" toml:"log"`
+	Tracing     Tracing     `yaml:"tracing" toml:"tracing"`
+	RateLimit   RateLimit   `yaml:"rate_limit" toml:"rate_limit"`
+	CORS        CORS        `yaml:"cors" toml:"cors"`
+	Compression Compression `yaml:"compression" toml:"compression"`
+	Cache       Cache       `yaml:"cache" toml:"cache"`
+}
+
+// Server holds http server settings.
+type Server struct {
+	Addr         string   `yaml:"addr" toml:"addr"`
+	ReadTimeout  Duration `yaml:"read_timeout" toml:"read_timeout"`
+	WriteTimeout Duration `yaml:"write_timeout" toml:"write_timeout"`
+	IdleTimeout  Duration `yaml:"idle_timeout" toml:"idle_timeout"`
+	// DrainPeriod is how long the server keeps serving
+	// after it's marked as not ready on shutdown.
+	DrainPeriod     Duration `yaml:"drain_period" toml:"drain_period"`
+	ShutdownTimeout Duration `yaml:"shutdown_timeout" toml:"shutdown_timeout"`
+}
+
+// Database holds database connection and pool settings.
+type Database struct {
+	DSN             string   `yaml:"dsn" toml:"dsn"`
+	ReplicaDSNs     []string `yaml:"replica_dsns" toml:"replica_dsns"`
+	MaxOpenConns    int      `yaml:"max_open_conns" toml:"max_open_conns"`
+	MaxIdleConns    int      `yaml:"max_idle_conns" toml:"max_idle_conns"`
+	ConnMaxLifetime Duration `yaml:"conn_max_lifetime" toml:"conn_max_lifetime"`
+	// ConnMaxIdleTime closes connections which stay idle that long,
+	// zero keeps them until ConnMaxLifetime.
+	ConnMaxIdleTime Duration `yaml:"conn_max_idle_time" toml:"conn_max_idle_time"`
+}
+
+// Log holds logger settings.
+type Log struct {
+	Level           string   `yaml:"level" toml:"level"`
+	Format          string   `yaml:"format" toml:"format"`
+	SensitiveFields []string `yaml:"sensitive_fields" toml:"sensitive_fields"`
+}
+
+// Tracing holds span export settings.
+type Tracing struct {
+	Exporter string `yaml:"exporter" toml:"exporter"`
+	// Endpoint is a host:port of OTLP/HTTP collector,
+	// OTEL_EXPORTER_OTLP_ENDPOINT is used if it's empty.
+	Endpoint    string  `yaml:"endpoint" toml:"endpoint"`
+	File        string  `yaml:"file" toml:"file"`
+	SampleRatio float64 `yaml:"sample_ratio" toml:"sample_ratio"`
+}
+
+// RateLimit holds request budgets of every authenticated user or
+// client ip, rates are in requests per second. It's disabled by
+// default as anonymous clients behind a proxy share its ip.
+type RateLimit struct {
+	Enabled    bool    `yaml:"enabled" toml:"enabled"`
+	ReadRate   float64 `yaml:"read_rate" toml:"read_rate"`
+	ReadBurst  int     `yaml:"read_burst" toml:"read_burst"`
+	WriteRate  float64 `yaml:"write_rate" toml:"write_rate"`
+	WriteBurst int     `yaml:"write_burst" toml:"write_burst"`
+}
+
+// CORS holds settings for browser clients from other origins,
+// it's disabled while there are no allowed origins. Credentials
+// are allowed for listed origins only, not for "*".
+type CORS struct {
+	AllowedOrigins   []string `yaml:"allowed_origins" toml:"allowed_origins"`
+	AllowedMethods   []string `yaml:"allowed_methods" toml:"allowed_methods"`
+	AllowedHeaders   []string `yaml:"allowed_headers" toml:"allowed_headers"`
+	AllowCredentials bool     `yaml:"allow_credentials" toml:"allow_credentials"`
+	MaxAge           Duration `yaml:"max_age" toml:"max_age"`
+}
+
+// Compression holds response compression settings.
+type Compression struct {
+	Enabled bool `yaml:"enabled" toml:"enabled"`
+	// MinSize is a size in bytes responses
+	// are compressed starting from.
+	MinSize int `yaml:"min_size" toml:"min_size"`
+}
+
+// Cache holds settings of the in-process card cache, it's off by
+// default as instances don't see each other's invalidations.
+type Cache struct {
+	Enabled bool `yaml:"enabled" toml:"enabled"`
+	// Size is how many cards are kept.
+	Size int      `yaml:"size" toml:"size"`
+	TTL  Duration `yaml:"ttl" toml:"ttl"`
+}
+
+// Duration allows to set time.Duration as a string like "15s".
+type Duration struct {
+	time.Duration
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler interface.
+func (d *Duration) UnmarshalText(text []byte) error {
+	v, err := time.ParseDuration(string(text))
+	if err != nil {
+		return err
+	}
+	d.Duration = v
+
+	return nil
+}
+
+// MarshalText implements encoding.TextMarshaler interface.
+func (d Duration) MarshalText() ([]byte, error) {
+	return []byte(d.String()), nil
+}
+
+// Default returns configuration with default values.
+func Default() Config {
+	c := Config{
+		Server: Server{
+			Addr:            ":8080",
+			ReadTimeout:     Duration{15 * time.Second},
+			WriteTimeout:    Duration{15 * time.Second},
+			IdleTimeout:     Duration{60 * time.Second},
+			DrainPeriod:     Duration{5 * time.Second},
+			ShutdownTimeout: Duration{15 * time.Second},
+		},
+		Database: Database{
+			MaxOpenConns:    25,
+			MaxIdleConns:    25,
+			ConnMaxLifetime: Duration{5 * time.Minute},
+			ConnMaxIdleTime: Duration{time.Minute},
+		},
+		Log: Log{
+			Level:  logrus.InfoLevel.String(),
+			Format: FormatJSON,
+			SensitiveFields: []string{
+				"password",
+				"token",
+			},
+		},
+		Tracing: Tracing{
+			Exporter:    ExporterNone,
+			SampleRatio: 1,
+		},
+		RateLimit: RateLimit{
+			ReadRate:   10,
+			ReadBurst:  20,
+			WriteRate:  2,
+			WriteBurst: 10,
+		},
+		CORS: CORS{
+			AllowedOrigins: []string{},
+			AllowedMethods: []string{"GET", "POST", "PUT", "DELETE"},
+			AllowedHeaders: []string{"Accept", "Content-Type", "X-Request-ID", "X-User-ID"},
+			MaxAge:         Duration{10 * time.Minute},
+		},
+		Compression: Compression{
+			Enabled: true,
+			MinSize: 1024,
+		},
+		Cache: Cache{
+			Size: 10000,
+			TTL:  Duration{time.Minute},
+		},
+	}
+
+	return c
+}
+
+// Load reads configuration from the file at path over defaults
+// and applies CARDS_* environment variables on top of it.
+// Empty path means the file isn't used.
+func Load(path string) (*Config, error) {
+	return load(path, os.LookupEnv)
+}
+
+func load(path string, lookupEnv func(string) (string, bool)) (*Config, error) {
+	c := Default()
+
+	if path != "" {
+		if err := c.readFile(path); err != nil {
+			return nil, fmt.Errorf("read file: %w", err)
+		}
+	}
+
+	if err := c.applyEnv(lookupEnv); err != nil {
+		return nil, fmt.Errorf("apply env: %w", err)
+	}
+
+	return &c, nil
+}
+
+func (c *Config) readFile(path string) error {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return err
+	}
+
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml":
+		if err := yaml.UnmarshalStrict(data, c); err != nil {
+			return fmt.Errorf("unmarshal yaml: %w", err)
+		}
+	case ".toml":
+		md, err := toml.Decode(string(data), c)
+		if err != nil {
+			return fmt.Errorf("decode toml: %w", err)
+		}
+		if undecoded := md.Undecoded(); len(undecoded) > 0 {
+			return fmt.Errorf("unknown toml keys: %v", undecoded)
+		}
+	default:
+		return fmt.Errorf("unsupported config format %q", filepath.Ext(path))
+	}
+
+	return nil
+}
+
+func (c *Config) applyEnv(lookupEnv func(string) (string, bool)) error {
+	vars := []struct {
+		name string
+		set  func(string) error
+	}{
+		{"ADDR", setString(&c.Server.Addr)},
+		{"READ_TIMEOUT", setDuration(&c.Server.ReadTimeout)},
+		{"WRITE_TIMEOUT", setDuration(&c.Server.WriteTimeout)},
+		{"IDLE_TIMEOUT", setDuration(&c.Server.IdleTimeout)},
+		{"DRAIN_PERIOD", setDuration(&c.Server.DrainPeriod)},
+		{"SHUTDOWN_TIMEOUT", setDuration(&c.Server.ShutdownTimeout)},
+		{"DSN", setString(&c.Database.DSN)},
+		{"DB_REPLICA_DSNS", setList(&c.Database.ReplicaDSNs)},
+		{"DB_MAX_OPEN_CONNS", setInt(&c.Database.MaxOpenConns)},
+		{"DB_MAX_IDLE_CONNS", setInt(&c.Database.MaxIdleConns)},
+		{"DB_CONN_MAX_LIFETIME", setDuration(&c.Database.ConnMaxLifetime)},
+		{"DB_CONN_MAX_IDLE_TIME", setDuration(&c.Database.ConnMaxIdleTime)},
+		{"LOG_LEVEL", setString(&c.Log.Level)},
+		{"LOG_FORMAT", setString(&c.Log.Format)},
+		{"LOG_SENSITIVE_FIELDS", setList(&c.Log.SensitiveFields)},
+		{"TRACING_EXPORTER", setString(&c.Tracing.Exporter)},
+		{"TRACING_ENDPOINT", setString(&c.Tracing.Endpoint)},
+		{"TRACING_FILE", setString(&c.Tracing.File)},
+		{"TRACING_SAMPLE_RATIO", setFloat(&c.Tracing.SampleRatio)},
+		{"RATE_LIMIT_ENABLED", setBool(&c.RateLimit.Enabled)},
+		{"RATE_LIMIT_READ_RATE", setFloat(&c.RateLimit.ReadRate)},
+		{"RATE_LIMIT_READ_BURST", setInt(&c.RateLimit.ReadBurst)},
+		{"RATE_LIMIT_WRITE_RATE", setFloat(&c.RateLimit.WriteRate)},
+		{"RATE_LIMIT_WRITE_BURST", setInt(&c.RateLimit.WriteBurst)},
+		{"CORS_ALLOWED_ORIGINS", setList(&c.CORS.AllowedOrigins)},
+		{"CORS_ALLOWED_METHODS", setList(&c.CORS.AllowedMethods)},
+		{"CORS_ALLOWED_HEADERS", setList(&c.CORS.AllowedHeaders)},
+		{"CORS_ALLOW_CREDENTIALS", setBool(&c.CORS.AllowCredentials)},
+		{"CORS_MAX_AGE", setDuration(&c.CORS.MaxAge)},
+		{"COMPRESSION_ENABLED", setBool(&c.Compression.Enabled)},
+		{"COMPRESSION_MIN_SIZE", setInt(&c.Compression.MinSize)},
+		{"CACHE_ENABLED", setBool(&c.Cache.Enabled)},
+		{"CACHE_SIZE", setInt(&c.Cache.Size)},
+		{"CACHE_TTL", setDuration(&c.Cache.TTL)},
+	}
+
+	for _, v := range vars {
+		value, ok := lookupEnv(envPrefix + v.name)
+		if !ok {
+			continue
+		}
+
+		if err := v.set(value); err != nil {
+			return fmt.Errorf("%s%s: %w", envPrefix, v.name, err)
+		}
+	}
+
+	return nil
+}
+
+func setString(dst *string) func(string) error {
+	return func(v string) error {
+		*dst = v
+		return nil
+	}
+}
+
+func setInt(dst *int) func(string) error {
+	return func(v string) error {
+		i, err := strconv.Atoi(v)
+		if err != nil {
+			return err
+		}
+		*dst = i
+		return nil
+	}
+}
+
+func setBool(dst *bool) func(string) error {
+	return func(v string) error {
+		b, err := strconv.ParseBool(v)
+		if err != nil {
+			return err
+		}
+		*dst = b
+		return nil
+	}
+}
+
+func setFloat(dst *float64) func(string) error {
+	return func(v string) error {
+		f, err := strconv.ParseFloat(v, 64)
+		if err != nil {
+			return err
+		}
+		*dst = f
+		return nil
+	}
+}
+
+func setDuration(dst *Duration) func(string) error {
+	return func(v string) error {
+		return dst.UnmarshalText([]byte(v))
+	}
+}
+
+func setList(dst *[]string) func(string) error {
+	return func(v string) error {
+		list := make([]string, 0)
+		for _, item := range strings.Split(v, ",") {
+			if item = strings.TrimSpace(item); item != "" {
+				list = append(list, item)
+			}
+		}
+		*dst = list
+		return nil
+	}
+}
+
+// Validate checks that configuration is usable.
+func (c *Config) Validate() error {
+	var errs []string
+
+	if c.Server.Addr == "" {
+		errs = append(errs, "server addr is required")
+	}
+
+	timeouts := []struct {
+		name string
+		d    Duration
+	}{
+		{"read_timeout", c.Server.ReadTimeout},
+		{"write_timeout", c.Server.WriteTimeout},
+		{"idle_timeout", c.Server.IdleTimeout},
+		{"shutdown_timeout", c.Server.ShutdownTimeout},
+	}
+
+	for _, t := range timeouts {
+		if t.d.Duration <= 0 {
+			errs = append(errs, fmt.Sprintf("server %s must be positive", t.name))
+		}
+	}
+
+	if c.Server.DrainPeriod.Duration < 0 {
+		errs = append(errs, "server drain_period can't be negative")
+	}
+
+	if c.Database.DSN == "" {
+		errs = append(errs, "database dsn is required")
+	}
+
+	for _, dsn := range c.Database.ReplicaDSNs {
+		if dsn == "" {
+			errs = append(errs, "database replica dsn can't be empty")
+			break
+		}
+	}
+
+	if c.Database.MaxOpenConns < 0 || c.Database.MaxIdleConns < 0 {
+		errs = append(errs, "database pool sizes can't be negative")
+	}
+
+	if c.Database.MaxOpenConns > 0 && c.Database.MaxIdleConns > c.Database.MaxOpenConns {
+		errs = append(errs, "database max_idle_conns can't exceed max_open_conns")
+	}
+
+	if c.Database.ConnMaxLifetime.Duration < 0 {
+		errs = append(errs, "database conn_max_lifetime can't be negative")
+	}
+
+	if c.Database.ConnMaxIdleTime.Duration < 0 {
+		errs = append(errs, "database conn_max_idle_time can't be negative")
+	}
+
+	if _, err := logrus.ParseLevel(c.Log.Level); err != nil {
+		errs = append(errs, fmt.Sprintf("log level: %v", err))
+	}
+
+	if c.Log.Format != FormatJSON && c.Log.Format != FormatText {
+		errs = append(errs, fmt.Sprintf("log format must be %q or %q", FormatJSON, FormatText))
+	}
+
+	switch c.Tracing.Exporter {
+	case ExporterNone, ExporterStdout, ExporterOTLP:
+	case ExporterFile:
+		if c.Tracing.File == "" {
+			errs = append(errs, "tracing file is required for file exporter")
+		}
+	default:
+		errs = append(errs, fmt.Sprintf("tracing exporter must be one of %q, %q, %q or %q",
+			ExporterNone, ExporterStdout, ExporterFile, ExporterOTLP))
+	}
+
+	if c.Tracing.SampleRatio < 0 || c.Tracing.SampleRatio > 1 {
+		errs = append(errs, "tracing sample_ratio must be between 0 and 1")
+	}
+
+	if c.RateLimit.Enabled {
+		if c.RateLimit.ReadRate <= 0 || c.RateLimit.WriteRate <= 0 {
+			errs = append(errs, "rate_limit rates must be positive")
+		}
+
+		if c.RateLimit.ReadBurst < 1 || c.RateLimit.WriteBurst < 1 {
+			errs = append(errs, "rate_limit bursts must be at least 1")
+		}
+	}
+
+	if len(c.CORS.AllowedOrigins) > 0 && len(c.CORS.AllowedMethods) == 0 {
+		errs = append(errs, "cors allowed_methods are required with allowed_origins")
+	}
+
+	if c.CORS.AllowCredentials {
+		for _, o := range c.CORS.AllowedOrigins {
+			if o == "*" {
+				errs = append(errs, `cors allow_credentials can't be used with "*" in allowed_origins`)
+				break
+			}
+		}
+	}
+
+	if c.CORS.MaxAge.Duration < 0 {
+		errs = append(errs, "cors max_age can't be negative")
+	}
+
+	if c.Compression.Enabled && c.Compression.MinSize < 1 {
+		errs = append(errs, "compression min_size must be positive")
+	}
+
+	if c.Cache.Enabled {
+		if c.Cache.Size < 1 {
+			errs = append(errs, "cache size must be positive")
+		}
+
+		if c.Cache.TTL.Duration <= 0 {
+			errs = append(errs, "cache ttl must be positive")
+		}
+	}
+
+	if len(errs) > 0 {
+		return errors.New(strings.Join(errs, "; "))
+	}
+
+	return nil
+}
+
+// Dump marshals configuration as yaml with secrets filtered.
+func (c *Config) Dump() ([]byte, error) {
+	printed := *c
+	printed.Database.DSN = filterDSN(c.Database.DSN)
+
+	printed.Database.ReplicaDSNs = make([]string, len(c.Database.ReplicaDSNs))
+	for i, dsn := range c.Database.ReplicaDSNs {
+		printed.Database.ReplicaDSNs[i] = filterDSN(dsn)
+	}
+
+	data, err := yaml.Marshal(&printed)
+	if err != nil {
+		return nil, fmt.Errorf("marshal yaml: %w", err)
+	}
+
+	return data, nil
+}
+
+// filterDSN hides password in both url and key=value DSN forms.
+func filterDSN(dsn string) string {
+	if u, err := url.Parse(dsn); err == nil && u.User != nil {
+		if _, ok := u.User.Password(); ok {
+			u.User = url.UserPassword(u.User.Username(), filtered)
+			return strings.Replace(u.String(), url.QueryEscape(filtered), filtered, 1)
+		}
+		return dsn
+	}
+
+	fields := strings.Fields(dsn)
+	for i, field := range fields {
+		if strings.HasPrefix(field, "password=") {
+			fields[i] = "password=" + filtered
+		}
+	}
+
+	return strings.Join(fields, " ")
+}
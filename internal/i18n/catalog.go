@@ -0,0 +1,50 @@
+package i18n
+
+import (
+	"golang.org/x/text/language"
+)
+
+// Message keys, they are stable and also serve as error codes.
+const (
+	BadRequest           = "bad_request"
+	NotFound             = "not_found"
+	NotAcceptable        = "not_acceptable"
+	UnsupportedMediaType = "unsupported_media_type"
+	TooManyRequests      = "too_many_requests"
+	InternalError        = "internal_error"
+	ValidationFailed     = "validation_failed"
+	Required             = "required"
+)
+
+var catalogs = map[language.Tag]map[string]string{
+	language.English: {
+		BadRequest:           "bad request",
+		NotFound:             "not found",
+		NotAcceptable:        "not acceptable",
+		UnsupportedMediaType: "unsupported media type",
+		TooManyRequests:      "too many requests",
+		InternalError:        "internal server error",
+		ValidationFailed:     "you have validation errors",
+		Required:             "cannot be blank",
+	},
+	language.Russian: {
+		BadRequest:           "некорректный запрос",
+		NotFound:             "не найдено",
+		NotAcceptable:        "формат ответа не поддерживается",
+		UnsupportedMediaType: "формат запроса не поддерживается",
+		TooManyRequests:      "слишком много запросов",
+		InternalError:        "внутренняя ошибка сервера",
+		ValidationFailed:     "в запросе есть ошибки",
+		Required:             "не может быть пустым",
+	},
+	language.Spanish: {
+		BadRequest:           "solicitud incorrecta",
+		NotFound:             "no encontrado",
+		NotAcceptable:        "formato de respuesta no admitido",
+		UnsupportedMediaType: "formato de solicitud no admitido",
+		TooManyRequests:      "demasiadas solicitudes",
+		InternalError:        "error interno del servidor",
+		ValidationFailed:     "hay errores de validación",
+		Required:             "no puede estar vacío",
+	},
+}
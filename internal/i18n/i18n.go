@@ -0,0 +1,42 @@
+package i18n
+
+import (
+	"golang.org/x/text/language"
+)
+
+// Supported lists languages having message catalogs,
+// the first one is used by default.
+var Supported = []language.Tag{
+	language.English,
+	language.Russian,
+	language.Spanish,
+}
+
+var matcher = language.NewMatcher(Supported)
+
+// Match picks the supported language which fits
+// the Accept-Language header the best.
+func Match(acceptLanguage string) language.Tag {
+	tags, _, err := language.ParseAcceptLanguage(acceptLanguage)
+	if err != nil {
+		return Supported[0]
+	}
+
+	_, idx, _ := matcher.Match(tags...)
+
+	return Supported[idx]
+}
+
+// Translate returns the message by key in the language,
+// it falls back to English and then to the key itself.
+func Translate(lang language.Tag, key string) string {
+	if msg, ok := catalogs[lang][key]; ok {
+		return msg
+	}
+
+	if msg, ok := catalogs[Supported[0]][key]; ok {
+		return msg
+	}
+
+	return key
+}
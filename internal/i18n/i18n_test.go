@@ -0,0 +1,69 @@
+package i18n
+
+import (
+	"testing"
+
+	"golang.org/x/text/language"
+)
+
+func TestMatch(t *testing.T) {
+	tests := []struct {
+		name   string
+		header string
+		expect language.Tag
+	}{
+		{"missing", "", language.English},
+		{"russian", "ru-RU,ru;q=0.9,en;q=0.8", language.Russian},
+		{"spanish region", "es-MX", language.Spanish},
+		{"by quality", "ru;q=0.5, es;q=0.8", language.Spanish},
+		{"unsupported", "de-DE", language.English},
+		{"malformed", "??", language.English},
+	}
+
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			if got := Match(tc.header); got != tc.expect {
+				t.Errorf("unexpected language: %s expected %s", got, tc.expect)
+			}
+		})
+	}
+}
+
+func TestTranslate(t *testing.T) {
+	tests := []struct {
+		name   string
+		lang   language.Tag
+		key    string
+		expect string
+	}{
+		{"english", language.English, NotFound, "not found"},
+		{"russian", language.Russian, Required, "не может быть пустым"},
+		{"spanish", language.Spanish, TooManyRequests, "demasiadas solicitudes"},
+		{"unsupported language", language.German, NotFound, "not found"},
+		{"unknown key", language.Russian, "unknown", "unknown"},
+	}
+
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			if got := Translate(tc.lang, tc.key); got != tc.expect {
+				t.Errorf("unexpected message: %q expected %q", got, tc.expect)
+			}
+		})
+	}
+}
+
+func TestCatalogsComplete(t *testing.T) {
+	for key := range catalogs[Supported[0]] {
+		for _, lang := range Supported[1:] {
+			if _, ok := catalogs[lang][key]; !ok {
+				t.Errorf("missing %q message in %s catalog", key, lang)
+			}
+		}
+	}
+}
@@ -2,6 +2,7 @@ package logger
 
 import (
 	"fmt"
+	"io"
 	"sync"
 
 	"github.com/sirupsen/logrus"
@@ -35,6 +36,22 @@ func SetFormatter(f logrus.Formatter) Option {
 	}
 }
 
+// SetOutput set logrus output
+func SetOutput(w io.Writer) Option {
+	return func(l *Logger) error {
+		l.logrus.Out = w
+		return nil
+	}
+}
+
+// SetLevel set logrus level
+func SetLevel(level logrus.Level) Option {
+	return func(l *Logger) error {
+		l.logrus.SetLevel(level)
+		return nil
+	}
+}
+
 // New initialize logger with dependencies.
 func New(options ...Option) (*Logger, error) {
 	l := Logger{
@@ -0,0 +1,26 @@
+package logger
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel/trace"
+)
+
+// TraceFields adds ids of the span from ctx to the extra
+// fields, so log lines can be matched with traces.
+func TraceFields(ctx context.Context, extra map[string]interface{}) map[string]interface{} {
+	sc := trace.SpanContextFromContext(ctx)
+	if !sc.IsValid() {
+		return extra
+	}
+
+	fields := make(map[string]interface{}, len(extra)+2)
+	for k, v := range extra {
+		fields[k] = v
+	}
+
+	fields["trace_id"] = sc.TraceID().String()
+	fields["span_id"] = sc.SpanID().String()
+
+	return fields
+}
@@ -0,0 +1,52 @@
+package logger
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func TestTraceFields(t *testing.T) {
+	traceID, _ := trace.TraceIDFromHex("4bf92f3577b34da6a3ce929d0e0e4736")
+	spanID, _ := trace.SpanIDFromHex("00f067aa0ba902b7")
+
+	sc := trace.NewSpanContext(trace.SpanContextConfig{
+		TraceID: traceID,
+		SpanID:  spanID,
+	})
+
+	tests := []struct {
+		name   string
+		ctx    context.Context
+		expect map[string]interface{}
+	}{
+		{
+			name:   "without span",
+			ctx:    context.Background(),
+			expect: map[string]interface{}{"path": "/"},
+		},
+		{
+			name: "with span",
+			ctx:  trace.ContextWithSpanContext(context.Background(), sc),
+			expect: map[string]interface{}{
+				"path":     "/",
+				"trace_id": "4bf92f3577b34da6a3ce929d0e0e4736",
+				"span_id":  "00f067aa0ba902b7",
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			extra := map[string]interface{}{"path": "/"}
+
+			assert.Equal(t, tc.expect, TraceFields(tc.ctx, extra))
+			assert.Len(t, extra, 1, "expected extra to stay untouched")
+		})
+	}
+}
@@ -0,0 +1,122 @@
+package ratelimit
+
+import (
+	"context"
+	"math"
+	"sync"
+	"time"
+)
+
+const sweepInterval = time.Minute
+
+// Limit is a token bucket budget.
+type Limit struct {
+	// Rate is how many tokens are added per second.
+	Rate float64
+	// Burst is the bucket size.
+	Burst int
+}
+
+// Result describes the bucket after taking a token.
+type Result struct {
+	Allowed   bool
+	Limit     int
+	Remaining int
+	// Reset is when the bucket is full again.
+	Reset time.Duration
+	// RetryAfter is when the next token is available
+	// if the request isn't allowed.
+	RetryAfter time.Duration
+}
+
+// Store keeps buckets by key, a shared store allows
+// to limit requests across several instances.
+type Store interface {
+	Take(ctx context.Context, key string, limit Limit) (Result, error)
+}
+
+type bucket struct {
+	tokens float64
+	last   time.Time
+	limit  Limit
+}
+
+// MemoryStore keeps buckets in memory of the process.
+type MemoryStore struct {
+	mu        sync.Mutex
+	buckets   map[string]*bucket
+	now       func() time.Time
+	lastSweep time.Time
+}
+
+// NewMemoryStore prepares the store to work.
+func NewMemoryStore() *MemoryStore {
+	s := MemoryStore{
+		buckets: make(map[string]*bucket),
+		now:     time.Now,
+	}
+
+	return &s
+}
+
+// Take implements Store interface.
+func (s *MemoryStore) Take(ctx context.Context, key string, limit Limit) (Result, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.now()
+	s.sweep(now)
+
+	b, ok := s.buckets[key]
+	if !ok {
+		b = &bucket{
+			tokens: float64(limit.Burst),
+			last:   now,
+		}
+		s.buckets[key] = b
+	}
+
+	b.limit = limit
+	b.tokens = math.Min(float64(limit.Burst), b.tokens+now.Sub(b.last).Seconds()*limit.Rate)
+	b.last = now
+
+	res := Result{
+		Limit: limit.Burst,
+	}
+
+	if b.tokens >= 1 {
+		b.tokens--
+		res.Allowed = true
+	} else {
+		res.RetryAfter = refill(1-b.tokens, limit.Rate)
+	}
+
+	res.Remaining = int(b.tokens)
+	res.Reset = refill(float64(limit.Burst)-b.tokens, limit.Rate)
+
+	return res, nil
+}
+
+// sweep drops buckets which are full again, so that
+// clients seen once don't stay in memory.
+func (s *MemoryStore) sweep(now time.Time) {
+	if now.Sub(s.lastSweep) < sweepInterval {
+		return
+	}
+	s.lastSweep = now
+
+	for key, b := range s.buckets {
+		if b.tokens+now.Sub(b.last).Seconds()*b.limit.Rate >= float64(b.limit.Burst) {
+			delete(s.buckets, key)
+		}
+	}
+}
+
+// refill returns how long it takes to add tokens.
+func refill(tokens, rate float64) time.Duration {
+	if rate <= 0 {
+		return 0
+	}
+
+	return time.Duration(math.Ceil(tokens / rate * float64(time.Second)))
+}
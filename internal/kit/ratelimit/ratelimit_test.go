@@ -0,0 +1,80 @@
+package ratelimit
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMemoryStoreTake(t *testing.T) {
+	now := time.Date(2020, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	s := NewMemoryStore()
+	s.now = func() time.Time { return now }
+
+	limit := Limit{Rate: 1, Burst: 2}
+	ctx := context.Background()
+
+	tests := []struct {
+		name    string
+		advance time.Duration
+		key     string
+		expect  Result
+	}{
+		{
+			name:   "first token",
+			key:    "a",
+			expect: Result{Allowed: true, Limit: 2, Remaining: 1, Reset: time.Second},
+		},
+		{
+			name:   "last token",
+			key:    "a",
+			expect: Result{Allowed: true, Limit: 2, Remaining: 0, Reset: 2 * time.Second},
+		},
+		{
+			name:   "exhausted",
+			key:    "a",
+			expect: Result{Allowed: false, Limit: 2, Remaining: 0, Reset: 2 * time.Second, RetryAfter: time.Second},
+		},
+		{
+			name:   "another key",
+			key:    "b",
+			expect: Result{Allowed: true, Limit: 2, Remaining: 1, Reset: time.Second},
+		},
+		{
+			name:    "refilled",
+			advance: 1500 * time.Millisecond,
+			key:     "a",
+			expect:  Result{Allowed: true, Limit: 2, Remaining: 0, Reset: 1500 * time.Millisecond},
+		},
+	}
+
+	for _, tc := range tests {
+		now = now.Add(tc.advance)
+
+		res, err := s.Take(ctx, tc.key, limit)
+		assert.Nil(t, err, tc.name)
+		assert.Equal(t, tc.expect, res, tc.name)
+	}
+}
+
+func TestMemoryStoreSweep(t *testing.T) {
+	now := time.Date(2020, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	s := NewMemoryStore()
+	s.now = func() time.Time { return now }
+
+	ctx := context.Background()
+
+	s.Take(ctx, "idle", Limit{Rate: 1, Burst: 10})
+	s.Take(ctx, "busy", Limit{Rate: 0.001, Burst: 10})
+
+	now = now.Add(2 * sweepInterval)
+	s.Take(ctx, "new", Limit{Rate: 1, Burst: 10})
+
+	assert.NotContains(t, s.buckets, "idle")
+	assert.Contains(t, s.buckets, "busy")
+	assert.Contains(t, s.buckets, "new")
+}
@@ -0,0 +1,31 @@
+package metrics
+
+import (
+	"database/sql"
+	"strings"
+	"testing"
+
+	_ "github.com/lib/pq"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+func TestDBStatsCollector(t *testing.T) {
+	db, err := sql.Open("postgres", "")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer db.Close()
+
+	db.SetMaxOpenConns(7)
+
+	expected := `
+# HELP cards_db_max_open_connections Maximum number of open connections to the database.
+# TYPE cards_db_max_open_connections gauge
+cards_db_max_open_connections{db_name="primary"} 7
+`
+
+	c := NewDBStatsCollector(db, "primary")
+	if err := testutil.CollectAndCompare(c, strings.NewReader(expected), "cards_db_max_open_connections"); err != nil {
+		t.Errorf("unexpected metrics: %v", err)
+	}
+}
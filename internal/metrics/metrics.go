@@ -0,0 +1,46 @@
+package metrics
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/dipress/cards/internal/card"
+	"github.com/dipress/cards/internal/validation"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+const namespace = "cards"
+
+// Error classes used as the result label.
+const (
+	resultOK         = "ok"
+	resultValidation = "validation"
+	resultNotFound   = "not_found"
+	resultInternal   = "internal"
+)
+
+// classify maps an error to a low cardinality class.
+func classify(err error) string {
+	var verr validation.Errors
+
+	switch {
+	case err == nil:
+		return resultOK
+	case errors.As(err, &verr):
+		return resultValidation
+	case errors.Is(err, card.ErrNotFound):
+		return resultNotFound
+	}
+
+	return resultInternal
+}
+
+func register(reg prometheus.Registerer, cs ...prometheus.Collector) error {
+	for _, c := range cs {
+		if err := reg.Register(c); err != nil {
+			return fmt.Errorf("register: %w", err)
+		}
+	}
+
+	return nil
+}
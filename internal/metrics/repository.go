@@ -0,0 +1,101 @@
+package metrics
+
+import (
+	"context"
+	"time"
+
+	"github.com/dipress/cards/internal/card"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+type cardRepository struct {
+	next       card.Repository
+	operations *prometheus.CounterVec
+	duration   *prometheus.HistogramVec
+}
+
+// NewCardRepository decorates the repository with
+// query counters and latency histograms.
+func NewCardRepository(next card.Repository, reg prometheus.Registerer) (card.Repository, error) {
+	r := cardRepository{
+		next: next,
+		operations: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: "card_repository",
+			Name:      "operations_total",
+			Help:      "Number of card repository operations by result.",
+		}, []string{"operation", "result"}),
+		duration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Subsystem: "card_repository",
+			Name:      "operation_duration_seconds",
+			Help:      "Duration of card repository operations.",
+			Buckets:   prometheus.DefBuckets,
+		}, []string{"operation"}),
+	}
+
+	if err := register(reg, r.operations, r.duration); err != nil {
+		return nil, err
+	}
+
+	return &r, nil
+}
+
+func (r *cardRepository) observe(operation string, start time.Time, err error) {
+	r.duration.WithLabelValues(operation).Observe(time.Since(start).Seconds())
+	r.operations.WithLabelValues(operation, classify(err)).Inc()
+}
+
+// Create implements card.Repository interface.
+func (r *cardRepository) Create(ctx context.Context, nc *card.NewCard, c *card.Card) error {
+	start := time.Now()
+	err := r.next.Create(ctx, nc, c)
+	r.observe("create", start, err)
+
+	return err
+}
+
+// Find implements card.Repository interface.
+func (r *cardRepository) Find(ctx context.Context, id int) (*card.Card, error) {
+	start := time.Now()
+	c, err := r.next.Find(ctx, id)
+	r.observe("find", start, err)
+
+	return c, err
+}
+
+// List implements card.Repository interface.
+func (r *cardRepository) List(ctx context.Context, userID int) ([]card.Card, error) {
+	start := time.Now()
+	cs, err := r.next.List(ctx, userID)
+	r.observe("list", start, err)
+
+	return cs, err
+}
+
+// Update implements card.Repository interface.
+func (r *cardRepository) Update(ctx context.Context, id int, c *card.Card) error {
+	start := time.Now()
+	err := r.next.Update(ctx, id, c)
+	r.observe("update", start, err)
+
+	return err
+}
+
+// Answer implements card.Repository interface.
+func (r *cardRepository) Answer(ctx context.Context, id int, correct bool) (*card.Card, error) {
+	start := time.Now()
+	c, err := r.next.Answer(ctx, id, correct)
+	r.observe("answer", start, err)
+
+	return c, err
+}
+
+// Delete implements card.Repository interface.
+func (r *cardRepository) Delete(ctx context.Context, id int) error {
+	start := time.Now()
+	err := r.next.Delete(ctx, id)
+	r.observe("delete", start, err)
+
+	return err
+}
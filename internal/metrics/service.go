@@ -0,0 +1,104 @@
+package metrics
+
+import (
+	"context"
+
+	"github.com/dipress/cards/internal/card"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// CardService is a card use case.
+type CardService interface {
+	Create(ctx context.Context, f *card.Form) (*card.Card, error)
+	Import(ctx context.Context, forms []card.Form) (*card.Cards, error)
+	Find(ctx context.Context, id int) (*card.Card, error)
+	List(ctx context.Context, userID int) (*card.Cards, error)
+	Update(ctx context.Context, id int, f *card.Form) (*card.Card, error)
+	Answer(ctx context.Context, id int, f *card.AnswerForm) (*card.Card, error)
+	Delete(ctx context.Context, id int) error
+}
+
+type cardService struct {
+	next       CardService
+	operations *prometheus.CounterVec
+}
+
+// NewCardService decorates the service with operation
+// counters split by error class.
+func NewCardService(next CardService, reg prometheus.Registerer) (CardService, error) {
+	s := cardService{
+		next: next,
+		operations: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: "card_service",
+			Name:      "operations_total",
+			Help:      "Number of card service operations by result.",
+		}, []string{"operation", "result"}),
+	}
+
+	if err := register(reg, s.operations); err != nil {
+		return nil, err
+	}
+
+	return &s, nil
+}
+
+func (s *cardService) observe(operation string, err error) {
+	s.operations.WithLabelValues(operation, classify(err)).Inc()
+}
+
+// Create implements CardService interface.
+func (s *cardService) Create(ctx context.Context, f *card.Form) (*card.Card, error) {
+	c, err := s.next.Create(ctx, f)
+	s.observe("create", err)
+
+	return c, err
+}
+
+// Import implements CardService interface.
+func (s *cardService) Import(ctx context.Context, forms []card.Form) (*card.Cards, error) {
+	cs, err := s.next.Import(ctx, forms)
+	s.observe("import", err)
+
+	return cs, err
+}
+
+// Find implements CardService interface.
+func (s *cardService) Find(ctx context.Context, id int) (*card.Card, error) {
+	c, err := s.next.Find(ctx, id)
+	s.observe("find", err)
+
+	return c, err
+}
+
+// List implements CardService interface.
+func (s *cardService) List(ctx context.Context, userID int) (*card.Cards, error) {
+	cs, err := s.next.List(ctx, userID)
+	s.observe("list", err)
+
+	return cs, err
+}
+
+// Update implements CardService interface.
+func (s *cardService) Update(ctx context.Context, id int, f *card.Form) (*card.Card, error) {
+	c, err := s.next.Update(ctx, id, f)
+	s.observe("update", err)
+
+	return c, err
+}
+
+// Answer implements CardService interface.
+func (s *cardService) Answer(ctx context.Context, id int, f *card.AnswerForm) (*card.Card, error) {
+	c, err := s.next.Answer(ctx, id, f)
+	s.observe("answer", err)
+
+	return c, err
+}
+
+// Delete implements CardService interface.
+func (s *cardService) Delete(ctx context.Context, id int) error {
+	err := s.next.Delete(ctx, id)
+	s.observe("delete", err)
+
+	return err
+}
@@ -0,0 +1,102 @@
+package metrics
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/dipress/cards/internal/card"
+	"github.com/dipress/cards/internal/validation"
+	gomock "github.com/golang/mock/gomock"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCardService(t *testing.T) {
+	tests := []struct {
+		name           string
+		repositoryFunc func(m *card.MockRepository)
+		validaterFunc  func(m *card.MockValidater)
+		result         string
+	}{
+		{
+			name: "ok",
+			repositoryFunc: func(m *card.MockRepository) {
+				m.EXPECT().Create(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil)
+			},
+			validaterFunc: func(m *card.MockValidater) {
+				m.EXPECT().Validate(gomock.Any(), gomock.Any()).Return(nil)
+			},
+			result: resultOK,
+		},
+		{
+			name:           "validation",
+			repositoryFunc: func(m *card.MockRepository) {},
+			validaterFunc: func(m *card.MockValidater) {
+				m.EXPECT().Validate(gomock.Any(), gomock.Any()).Return(validation.NewErrors())
+			},
+			result: resultValidation,
+		},
+		{
+			name: "not found",
+			repositoryFunc: func(m *card.MockRepository) {
+				m.EXPECT().Create(gomock.Any(), gomock.Any(), gomock.Any()).Return(fmt.Errorf("query: %w", card.ErrNotFound))
+			},
+			validaterFunc: func(m *card.MockValidater) {
+				m.EXPECT().Validate(gomock.Any(), gomock.Any()).Return(nil)
+			},
+			result: resultNotFound,
+		},
+		{
+			name: "internal",
+			repositoryFunc: func(m *card.MockRepository) {
+				m.EXPECT().Create(gomock.Any(), gomock.Any(), gomock.Any()).Return(errors.New("mock error"))
+			},
+			validaterFunc: func(m *card.MockValidater) {
+				m.EXPECT().Validate(gomock.Any(), gomock.Any()).Return(nil)
+			},
+			result: resultInternal,
+		},
+	}
+
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			repo := card.NewMockRepository(ctrl)
+			validater := card.NewMockValidater(ctrl)
+
+			tc.repositoryFunc(repo)
+			tc.validaterFunc(validater)
+
+			reg := prometheus.NewRegistry()
+
+			r, err := NewCardRepository(repo, reg)
+			assert.Nil(t, err)
+
+			s, err := NewCardService(card.NewService(r, validater), reg)
+			assert.Nil(t, err)
+
+			s.Create(context.Background(), &card.Form{})
+
+			svc := s.(*cardService)
+			assert.Equal(t, float64(1), testutil.ToFloat64(svc.operations.WithLabelValues("create", tc.result)))
+		})
+	}
+}
+
+func TestNewCardServiceRegistered(t *testing.T) {
+	reg := prometheus.NewRegistry()
+
+	_, err := NewCardService(nil, reg)
+	assert.Nil(t, err)
+
+	_, err = NewCardService(nil, reg)
+	assert.Error(t, err)
+}
@@ -0,0 +1,193 @@
+package cache
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"sync/atomic"
+
+	"github.com/dipress/cards/internal/card"
+	"github.com/prometheus/client_golang/prometheus"
+	"golang.org/x/sync/singleflight"
+)
+
+const namespace = "cards"
+
+// Lookup results used as the result label.
+const (
+	resultHit   = "hit"
+	resultMiss  = "miss"
+	resultError = "error"
+)
+
+// Store keeps encoded cards by key, a shared store allows
+// several instances to use the same cache. The store decides
+// how long the values live.
+type Store interface {
+	Get(ctx context.Context, key string) ([]byte, bool, error)
+	Set(ctx context.Context, key string, value []byte) error
+	Delete(ctx context.Context, key string) error
+}
+
+type cardRepository struct {
+	card.Repository
+
+	store Store
+	group singleflight.Group
+	// invalidations counts mutations, a find which raced
+	// with one doesn't put the card into the cache.
+	invalidations uint64
+
+	lookups *prometheus.CounterVec
+	errors  *prometheus.CounterVec
+}
+
+// NewCardRepository decorates the repository with a read-through
+// cache of Find results, concurrent misses of the same card make a
+// single query. Update, Answer and Delete invalidate the card.
+func NewCardRepository(next card.Repository, store Store, reg prometheus.Registerer) (card.Repository, error) {
+	r := cardRepository{
+		Repository: next,
+		store:      store,
+		lookups: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: "card_cache",
+			Name:      "lookups_total",
+			Help:      "Number of card cache lookups by result, the hit ratio is hits over all lookups.",
+		}, []string{"result"}),
+		errors: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: "card_cache",
+			Name:      "errors_total",
+			Help:      "Number of failed card cache operations.",
+		}, []string{"operation"}),
+	}
+
+	for _, c := range []prometheus.Collector{r.lookups, r.errors} {
+		if err := reg.Register(c); err != nil {
+			return nil, fmt.Errorf("register: %w", err)
+		}
+	}
+
+	return &r, nil
+}
+
+func cardKey(id int) string {
+	return "card:" + strconv.Itoa(id)
+}
+
+// Find returns the cached card or loads it from the repository,
+// a failing store is treated as a miss. Finds in a transaction
+// bypass the cache as they may see uncommitted changes.
+func (r *cardRepository) Find(ctx context.Context, id int) (*card.Card, error) {
+	if card.InTransaction(ctx) {
+		return r.Repository.Find(ctx, id)
+	}
+
+	key := cardKey(id)
+
+	value, ok, err := r.store.Get(ctx, key)
+	switch {
+	case err != nil:
+		r.lookups.WithLabelValues(resultError).Inc()
+	case ok:
+		var cd card.Card
+		if err := json.Unmarshal(value, &cd); err == nil {
+			r.lookups.WithLabelValues(resultHit).Inc()
+			return &cd, nil
+		}
+		r.lookups.WithLabelValues(resultError).Inc()
+	default:
+		r.lookups.WithLabelValues(resultMiss).Inc()
+	}
+
+	ch := r.group.DoChan(key, func() (interface{}, error) {
+		// The load is shared, a caller which gives
+		// up doesn't cancel it for the others.
+		ctx := context.WithoutCancel(ctx)
+		invalidations := atomic.LoadUint64(&r.invalidations)
+
+		cd, err := r.Repository.Find(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+
+		if atomic.LoadUint64(&r.invalidations) == invalidations {
+			r.set(ctx, key, cd)
+		}
+
+		return cd, nil
+	})
+
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	case res := <-ch:
+		if res.Err != nil {
+			return nil, res.Err
+		}
+
+		return copyCard(res.Val.(*card.Card)), nil
+	}
+}
+
+// Update implements card.Repository interface.
+func (r *cardRepository) Update(ctx context.Context, id int, c *card.Card) error {
+	defer r.invalidate(ctx, id)
+
+	return r.Repository.Update(ctx, id, c)
+}
+
+// Answer implements card.Repository interface.
+func (r *cardRepository) Answer(ctx context.Context, id int, correct bool) (*card.Card, error) {
+	defer r.invalidate(ctx, id)
+
+	return r.Repository.Answer(ctx, id, correct)
+}
+
+// Delete implements card.Repository interface.
+func (r *cardRepository) Delete(ctx context.Context, id int) error {
+	defer r.invalidate(ctx, id)
+
+	return r.Repository.Delete(ctx, id)
+}
+
+func (r *cardRepository) set(ctx context.Context, key string, cd *card.Card) {
+	value, err := json.Marshal(cd)
+	if err == nil {
+		err = r.store.Set(ctx, key, value)
+	}
+
+	if err != nil {
+		r.errors.WithLabelValues("set").Inc()
+	}
+}
+
+// invalidate drops the card even if the mutation failed, as it may
+// have been applied before the failure. A canceled request still
+// drops it. In a transaction the card is dropped after the commit,
+// a find outside could cache the old card until then.
+func (r *cardRepository) invalidate(ctx context.Context, id int) {
+	card.AfterCommit(ctx, func() {
+		key := cardKey(id)
+
+		atomic.AddUint64(&r.invalidations, 1)
+		r.group.Forget(key)
+
+		if err := r.store.Delete(context.WithoutCancel(ctx), key); err != nil {
+			r.errors.WithLabelValues("delete").Inc()
+		}
+	})
+}
+
+// copyCard keeps callers which share a load from sharing the card.
+func copyCard(cd *card.Card) *card.Card {
+	c := *cd
+	if cd.AnsweredAt != nil {
+		answeredAt := *cd.AnsweredAt
+		c.AnsweredAt = &answeredAt
+	}
+
+	return &c
+}
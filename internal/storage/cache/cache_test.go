@@ -0,0 +1,285 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/dipress/cards/internal/card"
+	gomock "github.com/golang/mock/gomock"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+)
+
+// failingStore fails every operation.
+type failingStore struct{}
+
+func (failingStore) Get(ctx context.Context, key string) ([]byte, bool, error) {
+	return nil, false, errors.New("store is down")
+}
+
+func (failingStore) Set(ctx context.Context, key string, value []byte) error {
+	return errors.New("store is down")
+}
+
+func (failingStore) Delete(ctx context.Context, key string) error {
+	return errors.New("store is down")
+}
+
+func TestFind(t *testing.T) {
+	cd := card.Card{ID: 1, UserID: 1, Word: "cache", Transcription: "kaSH", Translation: "кэш"}
+
+	tests := []struct {
+		name           string
+		store          Store
+		ctx            func(ctx context.Context) context.Context
+		repositoryFunc func(m *card.MockRepository)
+		mutate         func(ctx context.Context, r card.Repository)
+		lookups        map[string]float64
+		wantErr        bool
+	}{
+		{
+			name:  "hit",
+			store: NewLRU(10, time.Minute),
+			repositoryFunc: func(m *card.MockRepository) {
+				m.EXPECT().Find(gomock.Any(), cd.ID).Return(&cd, nil).Times(1)
+			},
+			lookups: map[string]float64{resultMiss: 1, resultHit: 1},
+		},
+		{
+			name:  "not found isn't cached",
+			store: NewLRU(10, time.Minute),
+			repositoryFunc: func(m *card.MockRepository) {
+				m.EXPECT().Find(gomock.Any(), cd.ID).Return(nil, card.ErrNotFound).Times(2)
+			},
+			lookups: map[string]float64{resultMiss: 2},
+			wantErr: true,
+		},
+		{
+			name:  "update invalidates",
+			store: NewLRU(10, time.Minute),
+			repositoryFunc: func(m *card.MockRepository) {
+				m.EXPECT().Find(gomock.Any(), cd.ID).Return(&cd, nil).Times(2)
+				m.EXPECT().Update(gomock.Any(), cd.ID, gomock.Any()).Return(nil)
+			},
+			mutate: func(ctx context.Context, r card.Repository) {
+				r.Update(ctx, cd.ID, &card.Card{})
+			},
+			lookups: map[string]float64{resultMiss: 2},
+		},
+		{
+			name:  "answer invalidates",
+			store: NewLRU(10, time.Minute),
+			repositoryFunc: func(m *card.MockRepository) {
+				m.EXPECT().Find(gomock.Any(), cd.ID).Return(&cd, nil).Times(2)
+				m.EXPECT().Answer(gomock.Any(), cd.ID, true).Return(&cd, nil)
+			},
+			mutate: func(ctx context.Context, r card.Repository) {
+				r.Answer(ctx, cd.ID, true)
+			},
+			lookups: map[string]float64{resultMiss: 2},
+		},
+		{
+			name:  "failed delete invalidates",
+			store: NewLRU(10, time.Minute),
+			repositoryFunc: func(m *card.MockRepository) {
+				m.EXPECT().Find(gomock.Any(), cd.ID).Return(&cd, nil).Times(2)
+				m.EXPECT().Delete(gomock.Any(), cd.ID).Return(errors.New("mock error"))
+			},
+			mutate: func(ctx context.Context, r card.Repository) {
+				r.Delete(ctx, cd.ID)
+			},
+			lookups: map[string]float64{resultMiss: 2},
+		},
+		{
+			name:  "failing store",
+			store: failingStore{},
+			repositoryFunc: func(m *card.MockRepository) {
+				m.EXPECT().Find(gomock.Any(), cd.ID).Return(&cd, nil).Times(2)
+			},
+			lookups: map[string]float64{resultError: 2},
+		},
+		{
+			name:  "transaction bypasses",
+			store: NewLRU(10, time.Minute),
+			ctx: func(ctx context.Context) context.Context {
+				ctx, _ = card.ContextWithTransaction(ctx)
+				return ctx
+			},
+			repositoryFunc: func(m *card.MockRepository) {
+				m.EXPECT().Find(gomock.Any(), cd.ID).Return(&cd, nil).Times(2)
+			},
+			lookups: map[string]float64{},
+		},
+	}
+
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			repo := card.NewMockRepository(ctrl)
+			tc.repositoryFunc(repo)
+
+			r, err := NewCardRepository(repo, tc.store, prometheus.NewRegistry())
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			ctx := context.Background()
+			if tc.ctx != nil {
+				ctx = tc.ctx(ctx)
+			}
+
+			for i := 0; i < 2; i++ {
+				got, err := r.Find(ctx, cd.ID)
+				if tc.wantErr {
+					assert.True(t, errors.Is(err, card.ErrNotFound))
+				} else {
+					assert.Nil(t, err)
+					assert.Equal(t, &cd, got)
+				}
+
+				if i == 0 && tc.mutate != nil {
+					tc.mutate(ctx, r)
+				}
+			}
+
+			lookups := r.(*cardRepository).lookups
+			for _, result := range []string{resultHit, resultMiss, resultError} {
+				assert.Equal(t, tc.lookups[result], testutil.ToFloat64(lookups.WithLabelValues(result)), result)
+			}
+		})
+	}
+}
+
+func TestInvalidateAfterCommit(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	old := card.Card{ID: 1, UserID: 1, Word: "old"}
+	updated := card.Card{ID: 1, UserID: 1, Word: "updated"}
+
+	repo := card.NewMockRepository(ctrl)
+	gomock.InOrder(
+		repo.EXPECT().Update(gomock.Any(), old.ID, gomock.Any()).Return(nil),
+		repo.EXPECT().Find(gomock.Any(), old.ID).Return(&old, nil),
+		repo.EXPECT().Find(gomock.Any(), old.ID).Return(&updated, nil),
+	)
+
+	r, err := NewCardRepository(repo, NewLRU(10, time.Minute), prometheus.NewRegistry())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	txCtx, committed := card.ContextWithTransaction(context.Background())
+	assert.Nil(t, r.Update(txCtx, old.ID, &card.Card{}))
+
+	// A find outside of the transaction sees the old card until the commit.
+	got, err := r.Find(context.Background(), old.ID)
+	assert.Nil(t, err)
+	assert.Equal(t, &old, got)
+
+	committed()
+
+	got, err = r.Find(context.Background(), old.ID)
+	assert.Nil(t, err)
+	assert.Equal(t, &updated, got)
+}
+
+func TestFindSingleflight(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	cd := card.Card{ID: 1, UserID: 1, Word: "flight"}
+
+	const callers = 8
+
+	var waiting sync.WaitGroup
+	waiting.Add(callers)
+	release := make(chan struct{})
+
+	repo := card.NewMockRepository(ctrl)
+	repo.EXPECT().Find(gomock.Any(), cd.ID).DoAndReturn(func(ctx context.Context, id int) (*card.Card, error) {
+		<-release
+		return &cd, nil
+	}).Times(1)
+
+	r, err := NewCardRepository(repo, NewLRU(10, time.Minute), prometheus.NewRegistry())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var done sync.WaitGroup
+	done.Add(callers)
+
+	cards := make([]*card.Card, callers)
+	for i := 0; i < callers; i++ {
+		go func(i int) {
+			defer done.Done()
+
+			waiting.Done()
+			got, err := r.Find(context.Background(), cd.ID)
+			assert.Nil(t, err)
+			cards[i] = got
+		}(i)
+	}
+
+	waiting.Wait()
+	time.Sleep(10 * time.Millisecond)
+	close(release)
+	done.Wait()
+
+	for i := 1; i < callers; i++ {
+		assert.Equal(t, cards[0], cards[i])
+		assert.NotSame(t, cards[0], cards[i], "expected callers to get copies")
+	}
+}
+
+func TestFindCanceled(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	cd := card.Card{ID: 1, UserID: 1, Word: "cancel"}
+
+	started := make(chan struct{})
+	release := make(chan struct{})
+
+	repo := card.NewMockRepository(ctrl)
+	repo.EXPECT().Find(gomock.Any(), cd.ID).DoAndReturn(func(ctx context.Context, id int) (*card.Card, error) {
+		close(started)
+		<-release
+		return &cd, ctx.Err()
+	}).Times(1)
+
+	r, err := NewCardRepository(repo, NewLRU(10, time.Minute), prometheus.NewRegistry())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+
+	canceled := make(chan error)
+	go func() {
+		_, err := r.Find(ctx, cd.ID)
+		canceled <- err
+	}()
+
+	<-started
+	cancel()
+	assert.True(t, errors.Is(<-canceled, context.Canceled))
+
+	close(release)
+
+	// The load outlives the canceled caller and fills the cache.
+	assert.Eventually(t, func() bool {
+		got, err := r.Find(context.Background(), cd.ID)
+		return err == nil && assert.ObjectsAreEqual(&cd, got)
+	}, time.Second, 10*time.Millisecond)
+}
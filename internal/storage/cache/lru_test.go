@@ -0,0 +1,69 @@
+package cache
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLRU(t *testing.T) {
+	now := time.Date(2020, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	c := NewLRU(2, time.Minute)
+	c.now = func() time.Time { return now }
+
+	ctx := context.Background()
+
+	get := func(key string) string {
+		value, ok, err := c.Get(ctx, key)
+		assert.Nil(t, err)
+		if !ok {
+			return ""
+		}
+		return string(value)
+	}
+
+	t.Log("with a full cache")
+	{
+		c.Set(ctx, "a", []byte("1"))
+		c.Set(ctx, "b", []byte("2"))
+
+		t.Log("\ttest:0\tshould evict the least recently used value")
+		{
+			assert.Equal(t, "1", get("a"))
+
+			c.Set(ctx, "c", []byte("3"))
+
+			assert.Equal(t, "", get("b"))
+			assert.Equal(t, "1", get("a"))
+			assert.Equal(t, "3", get("c"))
+			assert.Equal(t, 2, c.Len())
+		}
+
+		t.Log("\ttest:1\tshould replace the value")
+		{
+			c.Set(ctx, "a", []byte("4"))
+
+			assert.Equal(t, "4", get("a"))
+			assert.Equal(t, 2, c.Len())
+		}
+
+		t.Log("\ttest:2\tshould delete the value")
+		{
+			c.Delete(ctx, "c")
+
+			assert.Equal(t, "", get("c"))
+			assert.Equal(t, 1, c.Len())
+		}
+
+		t.Log("\ttest:3\tshould expire the value after the ttl")
+		{
+			now = now.Add(time.Minute)
+
+			assert.Equal(t, "", get("a"))
+			assert.Equal(t, 0, c.Len())
+		}
+	}
+}
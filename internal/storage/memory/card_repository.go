@@ -0,0 +1,181 @@
+package memory
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/dipress/cards/internal/card"
+)
+
+// CardRepository keeps cards in memory, it is safe
+// for concurrent use.
+type CardRepository struct {
+	mu     sync.RWMutex
+	lastID int
+	cards  map[int]card.Card
+}
+
+// NewCardRepository factory prepares the card repository to work.
+func NewCardRepository() *CardRepository {
+	r := CardRepository{
+		cards: make(map[int]card.Card),
+	}
+
+	return &r
+}
+
+// now returns the current time as Postgres stores timestamps.
+func now() time.Time {
+	return time.Now().UTC().Truncate(time.Microsecond)
+}
+
+// copyCard makes sure callers can't change stored cards.
+func copyCard(cd card.Card) *card.Card {
+	if cd.AnsweredAt != nil {
+		at := *cd.AnsweredAt
+		cd.AnsweredAt = &at
+	}
+
+	return &cd
+}
+
+// Create inserts a new card.
+func (r *CardRepository) Create(ctx context.Context, f *card.NewCard, ca *card.Card) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.lastID++
+	created := now()
+
+	cd := card.Card{
+		ID:            r.lastID,
+		UserID:        f.UserID,
+		Word:          f.Word,
+		Transcription: f.Transcription,
+		Translation:   f.Translation,
+		CreatedAt:     created,
+		UpdatedAt:     created,
+	}
+	r.cards[cd.ID] = cd
+
+	*ca = *copyCard(cd)
+
+	return nil
+}
+
+// Find finds a card by id.
+func (r *CardRepository) Find(ctx context.Context, id int) (*card.Card, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	cd, ok := r.cards[id]
+	if !ok {
+		return nil, card.ErrNotFound
+	}
+
+	return copyCard(cd), nil
+}
+
+// List lists cards by user id ordered by id.
+func (r *CardRepository) List(ctx context.Context, userID int) ([]card.Card, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	cards := make([]card.Card, 0)
+	for _, cd := range r.cards {
+		if cd.UserID == userID {
+			cards = append(cards, *copyCard(cd))
+		}
+	}
+
+	sort.Slice(cards, func(i, j int) bool {
+		return cards[i].ID < cards[j].ID
+	})
+
+	return cards, nil
+}
+
+// Update updates a card by id and fills it with the updated one.
+func (r *CardRepository) Update(ctx context.Context, id int, ca *card.Card) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	cd, ok := r.cards[id]
+	if !ok {
+		return card.ErrNotFound
+	}
+
+	cd.UserID = ca.UserID
+	cd.Word = ca.Word
+	cd.Transcription = ca.Transcription
+	cd.Translation = ca.Translation
+	cd.UpdatedAt = now()
+	r.cards[id] = cd
+
+	*ca = *copyCard(cd)
+
+	return nil
+}
+
+// Answer counts the learner's answer on a card by id.
+func (r *CardRepository) Answer(ctx context.Context, id int, correct bool) (*card.Card, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	cd, ok := r.cards[id]
+	if !ok {
+		return nil, card.ErrNotFound
+	}
+
+	if correct {
+		cd.CorrectAnswers++
+	} else {
+		cd.WrongAnswers++
+	}
+
+	answered := now()
+	cd.AnsweredAt = &answered
+	r.cards[id] = cd
+
+	return copyCard(cd), nil
+}
+
+// Delete deletes a card by id.
+func (r *CardRepository) Delete(ctx context.Context, id int) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.cards[id]; !ok {
+		return card.ErrNotFound
+	}
+
+	delete(r.cards, id)
+
+	return nil
+}
@@ -0,0 +1,14 @@
+package memory
+
+import (
+	"testing"
+
+	"github.com/dipress/cards/internal/card"
+	"github.com/dipress/cards/internal/storage/storagetest"
+)
+
+func TestCardRepository(t *testing.T) {
+	storagetest.TestCardRepository(t, func(t *testing.T) (card.Repository, func()) {
+		return NewCardRepository(), func() {}
+	})
+}
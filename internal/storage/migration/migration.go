@@ -0,0 +1,214 @@
+package migration
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// VersionLayout formats versions of new migrations.
+const VersionLayout = "20060102150405"
+
+// ErrNoChange raises when the schema is at the target version already.
+var ErrNoChange = errors.New("no change")
+
+var nameRe = regexp.MustCompile(`^[a-z0-9_]+$`)
+
+// Migration is a pair of up and down files of a schema version.
+type Migration struct {
+	Version uint
+	Name    string
+}
+
+// String returns the file name of the migration without the direction.
+func (m Migration) String() string {
+	return fmt.Sprintf("%d_%s", m.Version, m.Name)
+}
+
+// Migrator changes the schema of a database, the version
+// is zero while no migration is applied.
+type Migrator interface {
+	// Up applies all pending migrations.
+	Up() error
+	// Steps applies n migrations or rolls back -n of them.
+	Steps(n int) error
+	// Goto migrates up or down to the version.
+	Goto(version uint) error
+	// Force sets the version without running migrations and clears
+	// the dirty flag, -1 means no migration is applied.
+	Force(version int) error
+	Version() (version uint, dirty bool, err error)
+	Migrations() ([]Migration, error)
+}
+
+// Parse returns migrations of the file names ordered by version,
+// names look like 20200228130253_cards.up.sql.
+func Parse(names []string) ([]Migration, error) {
+	byVersion := make(map[uint]Migration)
+
+	for _, name := range names {
+		base := filepath.Base(name)
+		base = strings.TrimSuffix(base, ".sql")
+		base = strings.TrimSuffix(strings.TrimSuffix(base, ".up"), ".down")
+
+		i := strings.Index(base, "_")
+		if i < 0 {
+			return nil, fmt.Errorf("invalid migration name %q", name)
+		}
+
+		v, err := strconv.ParseUint(base[:i], 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("parse migration version %q: %w", name, err)
+		}
+
+		byVersion[uint(v)] = Migration{Version: uint(v), Name: base[i+1:]}
+	}
+
+	ms := make([]Migration, 0, len(byVersion))
+	for _, m := range byVersion {
+		ms = append(ms, m)
+	}
+
+	sort.Slice(ms, func(i, j int) bool {
+		return ms[i].Version < ms[j].Version
+	})
+
+	return ms, nil
+}
+
+// CheckPairs returns an error naming the migrations
+// which miss either the up or the down file.
+func CheckPairs(names []string) error {
+	files := make(map[string]map[string]bool)
+
+	for _, name := range names {
+		base := strings.TrimSuffix(filepath.Base(name), ".sql")
+
+		direction := filepath.Ext(base)
+		if direction != ".up" && direction != ".down" {
+			return fmt.Errorf("migration %q is neither up nor down", name)
+		}
+
+		base = strings.TrimSuffix(base, direction)
+		if files[base] == nil {
+			files[base] = make(map[string]bool)
+		}
+		files[base][direction] = true
+	}
+
+	var unpaired []string
+	for base, directions := range files {
+		for _, direction := range []string{".up", ".down"} {
+			if !directions[direction] {
+				unpaired = append(unpaired, base+direction+".sql")
+			}
+		}
+	}
+
+	if len(unpaired) > 0 {
+		sort.Strings(unpaired)
+		return fmt.Errorf("missing migrations: %s", strings.Join(unpaired, ", "))
+	}
+
+	return nil
+}
+
+// Latest returns the version of the last migration.
+func Latest(ms []Migration) uint {
+	if len(ms) == 0 {
+		return 0
+	}
+
+	return ms[len(ms)-1].Version
+}
+
+// Plan returns the migrations which move the schema from the current
+// version to the target one in the order they run, up tells whether
+// they are applied or rolled back.
+func Plan(ms []Migration, current, target uint) (up bool, steps []Migration) {
+	if target >= current {
+		for _, m := range ms {
+			if m.Version > current && m.Version <= target {
+				steps = append(steps, m)
+			}
+		}
+
+		return true, steps
+	}
+
+	for i := len(ms) - 1; i >= 0; i-- {
+		if ms[i].Version > target && ms[i].Version <= current {
+			steps = append(steps, ms[i])
+		}
+	}
+
+	return false, steps
+}
+
+// StepsTarget returns the version the schema gets after n steps from
+// the current version, negative n rolls migrations back.
+func StepsTarget(ms []Migration, current uint, n int) (uint, error) {
+	i := -1
+	for j, m := range ms {
+		if m.Version == current {
+			i = j
+		}
+	}
+
+	if current != 0 && i < 0 {
+		return 0, fmt.Errorf("unknown current version %d", current)
+	}
+
+	j := i + n
+	switch {
+	case j < -1:
+		return 0, fmt.Errorf("only %d migrations are applied", i+1)
+	case j >= len(ms):
+		return 0, fmt.Errorf("only %d migrations are pending", len(ms)-i-1)
+	case j == -1:
+		return 0, nil
+	}
+
+	return ms[j].Version, nil
+}
+
+// Find returns the migration of the version.
+func Find(ms []Migration, version uint) (Migration, bool) {
+	for _, m := range ms {
+		if m.Version == version {
+			return m, true
+		}
+	}
+
+	return Migration{}, false
+}
+
+// Create writes empty up and down files of a new migration to dir
+// and returns their paths.
+func Create(dir, name string, now time.Time) (up, down string, err error) {
+	if !nameRe.MatchString(name) {
+		return "", "", fmt.Errorf("migration name %q must consist of lowercase letters, digits and underscores", name)
+	}
+
+	base := filepath.Join(dir, now.UTC().Format(VersionLayout)+"_"+name)
+	up, down = base+".up.sql", base+".down.sql"
+
+	for _, path := range []string{up, down} {
+		f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0644)
+		if err != nil {
+			return "", "", fmt.Errorf("create file: %w", err)
+		}
+
+		if err := f.Close(); err != nil {
+			return "", "", fmt.Errorf("close file: %w", err)
+		}
+	}
+
+	return up, down, nil
+}
@@ -0,0 +1,221 @@
+package migration
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var migrations = []Migration{
+	{Version: 1, Name: "cards"},
+	{Version: 2, Name: "card_answers"},
+	{Version: 3, Name: "users"},
+}
+
+func TestParse(t *testing.T) {
+	ms, err := Parse([]string{
+		"migrations/2_card_answers.up.sql",
+		"migrations/1_cards.down.sql",
+		"3_users.up.sql",
+		"1_cards.up.sql",
+		"2_card_answers.down.sql",
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, migrations, ms)
+	assert.Equal(t, uint(3), Latest(ms))
+
+	_, err = Parse([]string{"cards.up.sql"})
+	assert.Error(t, err)
+
+	_, err = Parse([]string{"first_cards.up.sql"})
+	assert.Error(t, err)
+}
+
+func TestCheckPairs(t *testing.T) {
+	tests := []struct {
+		name    string
+		files   []string
+		wantErr string
+	}{
+		{
+			name:  "paired",
+			files: []string{"1_cards.up.sql", "1_cards.down.sql", "2_users.down.sql", "2_users.up.sql"},
+		},
+		{
+			name:    "missing down",
+			files:   []string{"1_cards.up.sql", "1_cards.down.sql", "2_users.up.sql"},
+			wantErr: "missing migrations: 2_users.down.sql",
+		},
+		{
+			name:    "missing up",
+			files:   []string{"1_cards.down.sql"},
+			wantErr: "missing migrations: 1_cards.up.sql",
+		},
+		{
+			name:    "no direction",
+			files:   []string{"1_cards.sql"},
+			wantErr: "neither up nor down",
+		},
+	}
+
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			err := CheckPairs(tc.files)
+			if tc.wantErr != "" {
+				if assert.Error(t, err) {
+					assert.Contains(t, err.Error(), tc.wantErr)
+				}
+				return
+			}
+
+			assert.Nil(t, err)
+		})
+	}
+}
+
+func TestPlan(t *testing.T) {
+	tests := []struct {
+		name    string
+		current uint
+		target  uint
+		up      bool
+		expect  []Migration
+	}{
+		{
+			name:   "up from scratch",
+			target: 3,
+			up:     true,
+			expect: migrations,
+		},
+		{
+			name:    "up one",
+			current: 1,
+			target:  2,
+			up:      true,
+			expect:  migrations[1:2],
+		},
+		{
+			name:    "down in reverse",
+			current: 3,
+			target:  1,
+			expect:  []Migration{migrations[2], migrations[1]},
+		},
+		{
+			name:    "no change",
+			current: 2,
+			target:  2,
+			up:      true,
+		},
+	}
+
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			up, steps := Plan(migrations, tc.current, tc.target)
+			assert.Equal(t, tc.up, up)
+			assert.Equal(t, tc.expect, steps)
+		})
+	}
+}
+
+func TestStepsTarget(t *testing.T) {
+	tests := []struct {
+		name    string
+		current uint
+		n       int
+		expect  uint
+		wantErr bool
+	}{
+		{
+			name:    "down one",
+			current: 3,
+			n:       -1,
+			expect:  2,
+		},
+		{
+			name:    "down all",
+			current: 3,
+			n:       -3,
+		},
+		{
+			name:    "down too many",
+			current: 1,
+			n:       -2,
+			wantErr: true,
+		},
+		{
+			name:   "up from scratch",
+			n:      2,
+			expect: 2,
+		},
+		{
+			name:    "up too many",
+			current: 2,
+			n:       2,
+			wantErr: true,
+		},
+		{
+			name:    "unknown version",
+			current: 7,
+			n:       -1,
+			wantErr: true,
+		},
+	}
+
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			target, err := StepsTarget(migrations, tc.current, tc.n)
+			if tc.wantErr {
+				assert.Error(t, err)
+				return
+			}
+
+			assert.Nil(t, err)
+			assert.Equal(t, tc.expect, target)
+		})
+	}
+}
+
+func TestCreate(t *testing.T) {
+	dir := t.TempDir()
+	now := time.Date(2020, 3, 10, 9, 30, 0, 0, time.UTC)
+
+	t.Log("with a migrations directory")
+	{
+		t.Log("\ttest:0\tshould create up and down files")
+		{
+			up, down, err := Create(dir, "users", now)
+			assert.Nil(t, err)
+			assert.Equal(t, filepath.Join(dir, "20200310093000_users.up.sql"), up)
+			assert.Equal(t, filepath.Join(dir, "20200310093000_users.down.sql"), down)
+
+			for _, path := range []string{up, down} {
+				_, err := os.Stat(path)
+				assert.Nil(t, err)
+			}
+		}
+
+		t.Log("\ttest:1\tshould not overwrite a migration")
+		{
+			_, _, err := Create(dir, "users", now)
+			assert.Error(t, err)
+		}
+
+		t.Log("\ttest:2\tshould reject an invalid name")
+		{
+			_, _, err := Create(dir, "Add users", now)
+			assert.Error(t, err)
+		}
+	}
+}
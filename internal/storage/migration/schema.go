@@ -0,0 +1,216 @@
+package migration
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"io/fs"
+
+	"github.com/golang-migrate/migrate/v4"
+	"github.com/golang-migrate/migrate/v4/database"
+	"github.com/golang-migrate/migrate/v4/source/iofs"
+)
+
+const source = "iofs"
+
+// Driver builds the migrate driver of the backend on db, table keeps
+// the schema version. release frees what the driver holds besides db,
+// like a connection taken from the pool, closing the driver itself
+// would close db.
+type Driver func(ctx context.Context, db *sql.DB, table string) (d database.Driver, release func() error, err error)
+
+// Schema runs the migrations embedded by a backend.
+type Schema struct {
+	name   string
+	fsys   fs.FS
+	dir    string
+	table  string
+	driver Driver
+}
+
+// NewSchema factory prepares the schema of the backend called name,
+// migrations are the *.sql files in dir of fsys.
+func NewSchema(name string, fsys fs.FS, dir, table string, driver Driver) *Schema {
+	s := Schema{
+		name:   name,
+		fsys:   fsys,
+		dir:    dir,
+		table:  table,
+		driver: driver,
+	}
+
+	return &s
+}
+
+// Migrate applies pending migrations, a schema
+// at the latest version isn't an error.
+func (s *Schema) Migrate(db *sql.DB) error {
+	if err := s.Migrator(db).Up(); err != nil && !errors.Is(err, ErrNoChange) {
+		return err
+	}
+
+	return nil
+}
+
+// Down rolls back all migrations.
+func (s *Schema) Down(db *sql.DB) error {
+	return s.run(db, func(m *migrate.Migrate) error { return m.Down() })
+}
+
+// Version returns the current schema version of the database,
+// zero version means no migration was applied.
+func (s *Schema) Version(ctx context.Context, db *sql.DB) (uint, bool, error) {
+	var (
+		version int64
+		dirty   bool
+	)
+
+	query := `SELECT version, dirty FROM ` + s.table + ` LIMIT 1`
+	if err := db.QueryRowContext(ctx, query).Scan(&version, &dirty); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return 0, false, nil
+		}
+
+		return 0, false, fmt.Errorf("query row scan: %w", err)
+	}
+
+	return uint(version), dirty, nil
+}
+
+// Migrations returns the embedded migrations ordered by version.
+func (s *Schema) Migrations() ([]Migration, error) {
+	names, err := fs.Glob(s.fsys, s.dir+"/*.sql")
+	if err != nil {
+		return nil, fmt.Errorf("glob migrations: %w", err)
+	}
+
+	return Parse(names)
+}
+
+// Files returns names of the embedded migration files.
+func (s *Schema) Files() ([]string, error) {
+	return fs.Glob(s.fsys, s.dir+"/*")
+}
+
+// ExpectedVersion returns the version of the latest migration.
+func (s *Schema) ExpectedVersion() (uint, error) {
+	ms, err := s.Migrations()
+	if err != nil {
+		return 0, fmt.Errorf("migrations: %w", err)
+	}
+
+	return Latest(ms), nil
+}
+
+// Check returns an error when the database schema
+// isn't at the expected version.
+func (s *Schema) Check(ctx context.Context, db *sql.DB) error {
+	expected, err := s.ExpectedVersion()
+	if err != nil {
+		return fmt.Errorf("expected version: %w", err)
+	}
+
+	version, dirty, err := s.Version(ctx, db)
+	if err != nil {
+		return fmt.Errorf("version: %w", err)
+	}
+
+	if dirty {
+		return fmt.Errorf("schema version %d is dirty", version)
+	}
+
+	if version != expected {
+		return fmt.Errorf("schema version %d, expected %d", version, expected)
+	}
+
+	return nil
+}
+
+// Migrator returns the migrator of the schema in db.
+func (s *Schema) Migrator(db *sql.DB) Migrator {
+	return &migrator{schema: s, db: db}
+}
+
+// run runs fn with a migrate instance, ErrNoChange
+// of the library is reported as ErrNoChange.
+func (s *Schema) run(db *sql.DB, fn func(m *migrate.Migrate) error) error {
+	src, err := iofs.New(s.fsys, s.dir)
+	if err != nil {
+		return fmt.Errorf("prepare source instance: %w", err)
+	}
+
+	d, release, err := s.driver(context.Background(), db, s.table)
+	if err != nil {
+		return fmt.Errorf("prepare database instance: %w", err)
+	}
+	defer release()
+
+	m, err := migrate.NewWithInstance(source, src, s.name, d)
+	if err != nil {
+		return fmt.Errorf("prepare migrate instance: %w", err)
+	}
+
+	if err := fn(m); err != nil {
+		if errors.Is(err, migrate.ErrNoChange) {
+			return ErrNoChange
+		}
+
+		return fmt.Errorf("migrate schema: %w", err)
+	}
+
+	return nil
+}
+
+type migrator struct {
+	schema *Schema
+	db     *sql.DB
+}
+
+// Up implements Migrator interface.
+func (m *migrator) Up() error {
+	return m.schema.run(m.db, func(mi *migrate.Migrate) error { return mi.Up() })
+}
+
+// Steps implements Migrator interface.
+func (m *migrator) Steps(n int) error {
+	return m.schema.run(m.db, func(mi *migrate.Migrate) error { return mi.Steps(n) })
+}
+
+// Goto implements Migrator interface.
+func (m *migrator) Goto(version uint) error {
+	return m.schema.run(m.db, func(mi *migrate.Migrate) error { return mi.Migrate(version) })
+}
+
+// Force implements Migrator interface.
+func (m *migrator) Force(version int) error {
+	return m.schema.run(m.db, func(mi *migrate.Migrate) error { return mi.Force(version) })
+}
+
+// Version implements Migrator interface.
+func (m *migrator) Version() (uint, bool, error) {
+	var (
+		version uint
+		dirty   bool
+	)
+
+	err := m.schema.run(m.db, func(mi *migrate.Migrate) error {
+		var err error
+		version, dirty, err = mi.Version()
+		if errors.Is(err, migrate.ErrNilVersion) {
+			return nil
+		}
+
+		return err
+	})
+	if err != nil {
+		return 0, false, fmt.Errorf("version: %w", err)
+	}
+
+	return version, dirty, nil
+}
+
+// Migrations implements Migrator interface.
+func (m *migrator) Migrations() ([]Migration, error) {
+	return m.schema.Migrations()
+}
@@ -5,67 +5,164 @@ import (
 	"database/sql"
 	"errors"
 	"fmt"
+	"strings"
+	"time"
 
 	"github.com/dipress/cards/internal/card"
 	"github.com/jmoiron/sqlx"
 )
 
-const (
-	driverName = "postgres"
-)
-
-// CardRepository holds CRUD actions.
+// CardRepository holds CRUD actions, they run in
+// the transaction of the context if there is one.
+// Queries are prepared once per database and reused.
 type CardRepository struct {
-	db *sqlx.DB
+	db       *sqlx.DB
+	stmts    *statements
+	replicas *replicas
 }
 
-// NewCardRepository factory prepares the card repository to work.
-func NewCardRepository(db *sql.DB) *CardRepository {
+// NewCardRepository factory prepares the card repository to work,
+// reads go to the replicas if there are any.
+func NewCardRepository(db *sql.DB, replicaDBs ...*sql.DB) *CardRepository {
+	xdb := sqlx.NewDb(db, driverName)
+
 	r := CardRepository{
-		db: sqlx.NewDb(db, driverName),
+		db:       xdb,
+		stmts:    newStatements(xdb),
+		replicas: newReplicas(replicaDBs),
 	}
 
 	return &r
 }
 
-const createCardQuery = `
-	INSERT INTO cards (word, transcription, translation, user_id)
-	VALUES ($1, $2, $3, $4)
-	RETURNING id, user_id, word, transcription, translation, created_at, updated_at
-`
+// Prepare prepares the queries on the primary so that a broken schema
+// fails at start, it has to run after migrations. Replicas prepare
+// the queries on first read as they may be down at the moment.
+func (r *CardRepository) Prepare(ctx context.Context) error {
+	for _, query := range queries {
+		if _, err := r.stmts.get(ctx, query); err != nil {
+			return fmt.Errorf("statements get: %w", err)
+		}
+	}
 
-// Create inserts a new card into the database.
-func (r *CardRepository) Create(ctx context.Context, f *card.NewCard, ca *card.Card) error {
-	if err := r.db.QueryRowContext(ctx, createCardQuery, f.Word, f.Transcription, f.Translation, f.UserID).Scan(
-		&ca.ID,
-		&ca.UserID,
-		&ca.Word,
-		&ca.Transcription,
-		&ca.Translation,
-		&ca.CreatedAt,
-		&ca.UpdatedAt,
-	); err != nil {
-		return fmt.Errorf("query context scan: %w", err)
+	return nil
+}
+
+// Close closes the prepared statements, the databases stay open.
+func (r *CardRepository) Close() error {
+	var errs []string
+
+	if err := r.stmts.close(); err != nil {
+		errs = append(errs, fmt.Sprintf("close primary statements: %v", err))
+	}
+
+	for i, rep := range r.replicas.list {
+		if err := rep.stmts.close(); err != nil {
+			errs = append(errs, fmt.Sprintf("close replica %d statements: %v", i+1, err))
+		}
+	}
+
+	if len(errs) > 0 {
+		return errors.New(strings.Join(errs, "; "))
 	}
 
 	return nil
 }
 
-const findCardQuery = `SELECT * FROM cards WHERE id = $1`
+// CheckReplicas pings the replicas, the failed ones
+// get no reads until they recover.
+func (r *CardRepository) CheckReplicas(ctx context.Context) error {
+	return r.replicas.check(ctx)
+}
 
-// Find finds a card by id.
-func (r *CardRepository) Find(ctx context.Context, id int) (*card.Card, error) {
-	var cd card.Card
+// read runs fn on a healthy replica and falls back to the primary if
+// the replica fails. Transactions and contexts which were used for
+// mutations read from the primary.
+func (r *CardRepository) read(ctx context.Context, fn func(q querier) error) error {
+	if _, ok := ctx.Value(txKey{}).(*sqlx.Tx); ok || wrote(ctx) {
+		return fn(r.primary(ctx))
+	}
+
+	rep := r.replicas.pick()
+	if rep == nil {
+		return fn(r.primary(ctx))
+	}
+
+	err := fn(prepared{q: rep.db, stmts: rep.stmts})
+	if !replicaFailed(ctx, err) {
+		return err
+	}
+
+	rep.markDown(time.Now())
+
+	return fn(r.primary(ctx))
+}
+
+// primary runs queries on the primary or in the transaction of the context.
+func (r *CardRepository) primary(ctx context.Context) querier {
+	q := conn(ctx, r.db)
+	tx, _ := q.(*sqlx.Tx)
+
+	return prepared{q: q, tx: tx, stmts: r.stmts}
+}
 
-	if err := r.db.QueryRowContext(ctx, findCardQuery, id).Scan(
+// cardColumns lists columns in the order scanCard expects.
+const cardColumns = `
+	id, user_id, word, transcription, translation,
+	correct_answers, wrong_answers, answered_at, created_at, updated_at
+`
+
+type scanner interface {
+	Scan(dest ...interface{}) error
+}
+
+func scanCard(s scanner, cd *card.Card) error {
+	return s.Scan(
 		&cd.ID,
 		&cd.UserID,
 		&cd.Word,
 		&cd.Transcription,
 		&cd.Translation,
+		&cd.CorrectAnswers,
+		&cd.WrongAnswers,
+		&cd.AnsweredAt,
 		&cd.CreatedAt,
 		&cd.UpdatedAt,
-	); err != nil {
+	)
+}
+
+const createCardQuery = `
+	INSERT INTO cards (word, transcription, translation, user_id)
+	VALUES ($1, $2, $3, $4)
+	RETURNING ` + cardColumns
+
+// Create inserts a new card into the database.
+func (r *CardRepository) Create(ctx context.Context, f *card.NewCard, ca *card.Card) (err error) {
+	ctx, span := startSpan(ctx, "INSERT", createCardQuery)
+	defer func() { endSpan(span, err) }()
+
+	markWrote(ctx)
+
+	row := r.primary(ctx).QueryRowContext(ctx, createCardQuery, f.Word, f.Transcription, f.Translation, f.UserID)
+	if err := scanCard(row, ca); err != nil {
+		return fmt.Errorf("query context scan: %w", err)
+	}
+
+	return nil
+}
+
+const findCardQuery = `SELECT ` + cardColumns + ` FROM cards WHERE id = $1`
+
+// Find finds a card by id.
+func (r *CardRepository) Find(ctx context.Context, id int) (_ *card.Card, err error) {
+	ctx, span := startSpan(ctx, "SELECT", findCardQuery)
+	defer func() { endSpan(span, err) }()
+
+	var cd card.Card
+
+	if err := r.read(ctx, func(q querier) error {
+		return scanCard(q.QueryRowContext(ctx, findCardQuery, id), &cd)
+	}); err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			return nil, card.ErrNotFound
 		}
@@ -76,63 +173,146 @@ func (r *CardRepository) Find(ctx context.Context, id int) (*card.Card, error) {
 	return &cd, nil
 }
 
-const updateCardQuery = `
-	UPDATE 
-		cards 
-	SET 
-		user_id=:user_id, 
-		word=:word,
-		transcription=:transcription,
-		translation=:translation,
-		updated_at=now() 
-	WHERE 
-		id=:id
-	`
-
-// Update updates a card by id
-func (r *CardRepository) Update(ctx context.Context, id int, ca *card.Card) error {
-	stmt, err := r.db.PrepareNamed(updateCardQuery)
+const listCardsQuery = `
+	SELECT ` + cardColumns + `
+	FROM cards
+	WHERE user_id = $1
+	ORDER BY id
+`
+
+// List lists cards by user id.
+func (r *CardRepository) List(ctx context.Context, userID int) (_ []card.Card, err error) {
+	ctx, span := startSpan(ctx, "SELECT", listCardsQuery)
+	defer func() { endSpan(span, err) }()
+
+	var cards []card.Card
+
+	if err := r.read(ctx, func(q querier) error {
+		var err error
+		cards, err = listCards(ctx, q, userID)
+		return err
+	}); err != nil {
+		return nil, err
+	}
+
+	return cards, nil
+}
+
+func listCards(ctx context.Context, q querier, userID int) ([]card.Card, error) {
+	rows, err := q.QueryContext(ctx, listCardsQuery, userID)
 	if err != nil {
-		return fmt.Errorf("prepare named: %w", err)
+		return nil, fmt.Errorf("query context: %w", err)
 	}
-	defer stmt.Close()
+	defer rows.Close()
 
-	if _, err := stmt.ExecContext(ctx, map[string]interface{}{
-		"id":            id,
-		"user_id":       ca.UserID,
-		"word":          ca.Word,
-		"transcription": ca.Transcription,
-		"translation":   ca.Translation,
-	}); err != nil {
+	cards := make([]card.Card, 0)
+	for rows.Next() {
+		var cd card.Card
+		if err := scanCard(rows, &cd); err != nil {
+			return nil, fmt.Errorf("rows scan: %w", err)
+		}
+
+		cards = append(cards, cd)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows: %w", err)
+	}
+
+	return cards, nil
+}
+
+const updateCardQuery = `
+	UPDATE
+		cards
+	SET
+		user_id=$2,
+		word=$3,
+		transcription=$4,
+		translation=$5,
+		updated_at=now()
+	WHERE
+		id=$1
+	RETURNING ` + cardColumns
+
+// Update updates a card by id and fills it with the updated row.
+func (r *CardRepository) Update(ctx context.Context, id int, ca *card.Card) (err error) {
+	ctx, span := startSpan(ctx, "UPDATE", updateCardQuery)
+	defer func() { endSpan(span, err) }()
+
+	markWrote(ctx)
+
+	row := r.primary(ctx).QueryRowContext(ctx, updateCardQuery, id, ca.UserID, ca.Word, ca.Transcription, ca.Translation)
+	if err := scanCard(row, ca); err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			return card.ErrNotFound
 		}
 
-		return fmt.Errorf("exec context: %w", err)
+		return fmt.Errorf("query row scan: %w", err)
 	}
 
 	return nil
 }
 
-const deleteCardQuery = `DELETE FROM cards WHERE id=:id`
+const answerCardQuery = `
+	UPDATE
+		cards
+	SET
+		correct_answers=correct_answers + $2,
+		wrong_answers=wrong_answers + $3,
+		answered_at=now()
+	WHERE
+		id=$1
+	RETURNING ` + cardColumns
 
-// Delete deletes a card by id
-func (r *CardRepository) Delete(ctx context.Context, id int) error {
-	stmt, err := r.db.PrepareNamed(deleteCardQuery)
-	if err != nil {
-		return fmt.Errorf("prepare named: %w", err)
+// Answer counts the learner's answer on a card by id.
+func (r *CardRepository) Answer(ctx context.Context, id int, correct bool) (_ *card.Card, err error) {
+	ctx, span := startSpan(ctx, "UPDATE", answerCardQuery)
+	defer func() { endSpan(span, err) }()
+
+	markWrote(ctx)
+
+	var right, wrong int
+	if correct {
+		right = 1
+	} else {
+		wrong = 1
 	}
-	defer stmt.Close()
 
-	if _, err := stmt.ExecContext(ctx, map[string]interface{}{
-		"id": id,
-	}); err != nil {
+	var cd card.Card
+	if err := scanCard(r.primary(ctx).QueryRowContext(ctx, answerCardQuery, id, right, wrong), &cd); err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
-			return card.ErrNotFound
+			return nil, card.ErrNotFound
 		}
 
+		return nil, fmt.Errorf("query row scan: %w", err)
+	}
+
+	return &cd, nil
+}
+
+const deleteCardQuery = `DELETE FROM cards WHERE id=$1`
+
+// Delete deletes a card by id
+func (r *CardRepository) Delete(ctx context.Context, id int) (err error) {
+	ctx, span := startSpan(ctx, "DELETE", deleteCardQuery)
+	defer func() { endSpan(span, err) }()
+
+	markWrote(ctx)
+
+	res, err := r.primary(ctx).ExecContext(ctx, deleteCardQuery, id)
+	if err != nil {
 		return fmt.Errorf("exec context: %w", err)
 	}
 
+	n, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("rows affected: %w", err)
+	}
+
+	if n == 0 {
+		return card.ErrNotFound
+	}
+
 	return nil
 }
@@ -5,144 +5,90 @@ import (
 	"testing"
 
 	"github.com/dipress/cards/internal/card"
+	"github.com/dipress/cards/internal/storage/storagetest"
+	"github.com/jmoiron/sqlx"
 )
 
-func TestCreateCard(t *testing.T) {
-	t.Log("with initialized repository")
-	{
-		db, teardown := postgresDB(t)
-		defer teardown()
-
-		r := NewCardRepository(db)
-
-		ctx, cancel := context.WithCancel(context.Background())
-		defer cancel()
-
-		t.Log("\ttest:0\tshould create the card into the database")
-		{
-			nc := card.NewCard{
-				UserID:        1,
-				Word:          "exceed",
-				Transcription: "ikˈsēd",
-				Translation:   "превышать",
-			}
-
-			var cd card.Card
-			err := r.Create(ctx, &nc, &cd)
-			if err != nil {
-				t.Errorf("unexpected error: %v", err)
-			}
-
-			if cd.ID == 0 {
-				t.Error("expected to parse returned id")
-			}
-		}
+func TestCardRepository(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping test in short mode")
 	}
-}
 
-func TestFindCard(t *testing.T) {
-	t.Log("with initialized repository")
-	{
+	storagetest.TestCardRepository(t, func(t *testing.T) (card.Repository, func()) {
 		db, teardown := postgresDB(t)
-		defer teardown()
-
-		r := NewCardRepository(db)
-
-		ctx, cancel := context.WithCancel(context.Background())
-		defer cancel()
+		return NewCardRepository(db), func() { teardown() }
+	})
+}
 
-		nc := card.NewCard{
-			UserID:        2,
-			Word:          "exceed",
-			Transcription: "ikˈsēd",
-			Translation:   "превышать",
-		}
+// Benchmarks compare the cost of a request when the statement is
+// prepared and closed on every call, not prepared at all and
+// prepared once by the repository.
+func BenchmarkFind(b *testing.B) {
+	benchmarkQuery(b, findCardQuery, func(ctx context.Context, r *CardRepository, id int) error {
+		_, err := r.Find(ctx, id)
+		return err
+	})
+}
 
-		var cd card.Card
-		err := r.Create(ctx, &nc, &cd)
-		if err != nil {
-			t.Errorf("unexpected error: %v", err)
-		}
+func BenchmarkUpdate(b *testing.B) {
+	benchmarkQuery(b, updateCardQuery, func(ctx context.Context, r *CardRepository, id int) error {
+		cd := card.Card{UserID: 1, Word: "cost", Transcription: "kôst", Translation: "стоимость"}
+		return r.Update(ctx, id, &cd)
+	}, 1, "cost", "kôst", "стоимость")
+}
 
-		t.Log("\ttest:0\tshould find the card into the database")
-		{
-			_, err := r.Find(ctx, cd.ID)
-			if err != nil {
-				t.Errorf("unexpected error: %v", err)
-			}
-		}
+func benchmarkQuery(b *testing.B, query string, run func(ctx context.Context, r *CardRepository, id int) error, args ...interface{}) {
+	if testing.Short() {
+		b.Skip("skipping benchmark in short mode")
 	}
-}
 
-func TestUpdateCard(t *testing.T) {
-	t.Log("with initialized repository")
-	{
-		db, teardown := postgresDB(t)
-		defer teardown()
+	ctx := context.Background()
 
-		r := NewCardRepository(db)
+	var cd card.Card
+	nc := card.NewCard{UserID: 1, Word: "cost", Transcription: "kôst", Translation: "стоимость"}
+	if err := NewCardRepository(db).Create(ctx, &nc, &cd); err != nil {
+		b.Fatalf("create card: %v", err)
+	}
 
-		ctx, cancel := context.WithCancel(context.Background())
-		defer cancel()
+	b.Run("prepare per call", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			stmt, err := db.PrepareContext(ctx, query)
+			if err != nil {
+				b.Fatalf("prepare: %v", err)
+			}
 
-		nc := card.NewCard{
-			UserID:        3,
-			Word:          "grow",
-			Transcription: "grō",
-			Translation:   "расти",
-		}
+			var got card.Card
+			if err := scanCard(stmt.QueryRowContext(ctx, append([]interface{}{cd.ID}, args...)...), &got); err != nil {
+				b.Fatalf("scan: %v", err)
+			}
 
-		var cd card.Card
-		err := r.Create(ctx, &nc, &cd)
-		if err != nil {
-			t.Errorf("unexpected error: %v", err)
+			stmt.Close()
 		}
+	})
 
-		t.Log("\ttest:0\tshould update the card into the database")
-		{
-			cd.Word = "climb"
-			cd.Transcription = "klīm"
-			cd.Translation = "взбираться"
+	b.Run("unprepared", func(b *testing.B) {
+		r := &CardRepository{db: sqlx.NewDb(db, driverName), replicas: newReplicas(nil)}
 
-			err := r.Update(ctx, 3, &cd)
-			if err != nil {
-				t.Errorf("unexpected error: %v", err)
+		for i := 0; i < b.N; i++ {
+			if err := run(ctx, r, cd.ID); err != nil {
+				b.Fatalf("unexpected error: %v", err)
 			}
 		}
-	}
-}
-
-func TestDeleteCard(t *testing.T) {
-	t.Log("with initialized repository")
-	{
-		db, teardown := postgresDB(t)
-		defer teardown()
+	})
 
+	b.Run("prepared", func(b *testing.B) {
 		r := NewCardRepository(db)
+		defer r.Close()
 
-		ctx, cancel := context.WithCancel(context.Background())
-		defer cancel()
-
-		nc := card.NewCard{
-			UserID:        4,
-			Word:          "srpead",
-			Transcription: "spred",
-			Translation:   "распространять",
+		if err := r.Prepare(ctx); err != nil {
+			b.Fatalf("prepare: %v", err)
 		}
 
-		var cd card.Card
-		err := r.Create(ctx, &nc, &cd)
-		if err != nil {
-			t.Errorf("unexpected error: %v", err)
-		}
-
-		t.Log("\ttest:0\tshould delete the card into the database")
-		{
-			err := r.Delete(ctx, cd.ID)
-			if err != nil {
-				t.Errorf("unexpected error: %v", err)
+		b.ResetTimer()
+		for i := 0; i < b.N; i++ {
+			if err := run(ctx, r, cd.ID); err != nil {
+				b.Fatalf("unexpected error: %v", err)
 			}
-
 		}
-	}
+	})
 }
@@ -0,0 +1,128 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"sync/atomic"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// replicaCooldown is how long a failed replica gets no reads.
+const replicaCooldown = 10 * time.Second
+
+// replica is a read-only copy of the primary database.
+type replica struct {
+	db    *sqlx.DB
+	stmts *statements
+	// downUntil is unix nano time until the replica is skipped.
+	downUntil int64
+}
+
+func (r *replica) healthy(now time.Time) bool {
+	return atomic.LoadInt64(&r.downUntil) <= now.UnixNano()
+}
+
+func (r *replica) markDown(now time.Time) {
+	atomic.StoreInt64(&r.downUntil, now.Add(replicaCooldown).UnixNano())
+}
+
+func (r *replica) markUp() {
+	atomic.StoreInt64(&r.downUntil, 0)
+}
+
+// replicas spreads reads over healthy replicas in turns.
+type replicas struct {
+	list []*replica
+	next uint32
+}
+
+func newReplicas(dbs []*sql.DB) *replicas {
+	rs := replicas{
+		list: make([]*replica, len(dbs)),
+	}
+
+	for i, db := range dbs {
+		rdb := sqlx.NewDb(db, driverName)
+		rs.list[i] = &replica{db: rdb, stmts: newStatements(rdb)}
+	}
+
+	return &rs
+}
+
+// pick returns the next healthy replica or nil when there is none.
+func (rs *replicas) pick() *replica {
+	n := uint32(len(rs.list))
+	if n == 0 {
+		return nil
+	}
+
+	now := time.Now()
+	start := atomic.AddUint32(&rs.next, 1)
+
+	for i := uint32(0); i < n; i++ {
+		if r := rs.list[(start+i)%n]; r.healthy(now) {
+			return r
+		}
+	}
+
+	return nil
+}
+
+// check pings every replica and updates its health.
+func (rs *replicas) check(ctx context.Context) error {
+	var failed int
+
+	for _, r := range rs.list {
+		if err := r.db.PingContext(ctx); err != nil {
+			r.markDown(time.Now())
+			failed++
+			continue
+		}
+
+		r.markUp()
+	}
+
+	if failed > 0 {
+		return errors.New("replicas are unavailable")
+	}
+
+	return nil
+}
+
+// replicaFailed tells failures of the replica itself
+// from results which the primary would return as well.
+func replicaFailed(ctx context.Context, err error) bool {
+	if err == nil || errors.Is(err, sql.ErrNoRows) {
+		return false
+	}
+
+	return ctx.Err() == nil
+}
+
+type readYourWritesKey struct{}
+
+// readYourWrites remembers that the context was used for a mutation.
+type readYourWrites struct {
+	wrote int32
+}
+
+// WithReadYourWrites makes reads go to the primary once the returned
+// context was used for a mutation, so that the caller sees its own
+// writes which replicas may not have yet.
+func WithReadYourWrites(ctx context.Context) context.Context {
+	return context.WithValue(ctx, readYourWritesKey{}, &readYourWrites{})
+}
+
+func markWrote(ctx context.Context) {
+	if ryw, ok := ctx.Value(readYourWritesKey{}).(*readYourWrites); ok {
+		atomic.StoreInt32(&ryw.wrote, 1)
+	}
+}
+
+func wrote(ctx context.Context) bool {
+	ryw, ok := ctx.Value(readYourWritesKey{}).(*readYourWrites)
+	return ok && atomic.LoadInt32(&ryw.wrote) == 1
+}
@@ -0,0 +1,184 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// unreachableDB fails on connection only, it never connects.
+func unreachableDB(t *testing.T) *sql.DB {
+	db, err := sql.Open(driverName, "host=127.0.0.1 port=1 user=test dbname=test sslmode=disable connect_timeout=1")
+	if err != nil {
+		t.Fatalf("open postgres: %v", err)
+	}
+
+	return db
+}
+
+func TestRead(t *testing.T) {
+	errReplica := errors.New("connection refused")
+
+	tests := []struct {
+		name       string
+		replicas   int
+		ctx        func(ctx context.Context) context.Context
+		replicaErr error
+		primary    int
+		replica    int
+		down       bool
+	}{
+		{
+			name:    "without replicas",
+			primary: 1,
+		},
+		{
+			name:     "replica",
+			replicas: 1,
+			replica:  1,
+		},
+		{
+			name:       "no rows from replica",
+			replicas:   1,
+			replicaErr: sql.ErrNoRows,
+			replica:    1,
+		},
+		{
+			name:       "fallback to primary",
+			replicas:   1,
+			replicaErr: errReplica,
+			primary:    1,
+			replica:    1,
+			down:       true,
+		},
+		{
+			name:     "read your writes",
+			replicas: 1,
+			ctx: func(ctx context.Context) context.Context {
+				ctx = WithReadYourWrites(ctx)
+				markWrote(ctx)
+				return ctx
+			},
+			primary: 1,
+		},
+		{
+			name:     "read your writes without writes",
+			replicas: 1,
+			ctx:      WithReadYourWrites,
+			replica:  1,
+		},
+		{
+			name:     "transaction",
+			replicas: 1,
+			ctx: func(ctx context.Context) context.Context {
+				return context.WithValue(ctx, txKey{}, &sqlx.Tx{})
+			},
+			primary: 1,
+		},
+	}
+
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			primary := unreachableDB(t)
+			defer primary.Close()
+
+			replicaDBs := make([]*sql.DB, tc.replicas)
+			for i := range replicaDBs {
+				replicaDBs[i] = unreachableDB(t)
+				defer replicaDBs[i].Close()
+			}
+
+			r := NewCardRepository(primary, replicaDBs...)
+
+			ctx := context.Background()
+			if tc.ctx != nil {
+				ctx = tc.ctx(ctx)
+			}
+
+			var primaryReads, replicaReads int
+			r.read(ctx, func(q querier) error {
+				if q.(prepared).stmts == r.stmts {
+					primaryReads++
+					return nil
+				}
+
+				replicaReads++
+				return tc.replicaErr
+			})
+
+			if primaryReads != tc.primary || replicaReads != tc.replica {
+				t.Errorf("unexpected reads: primary %d replica %d expected: primary %d replica %d",
+					primaryReads, replicaReads, tc.primary, tc.replica)
+			}
+
+			for _, rep := range r.replicas.list {
+				if down := !rep.healthy(time.Now()); down != tc.down {
+					t.Errorf("unexpected replica down: %t expected: %t", down, tc.down)
+				}
+			}
+		})
+	}
+}
+
+func TestReplicasPick(t *testing.T) {
+	t.Log("with two replicas")
+	{
+		a, b := unreachableDB(t), unreachableDB(t)
+		defer a.Close()
+		defer b.Close()
+
+		rs := newReplicas([]*sql.DB{a, b})
+
+		t.Log("\ttest:0\tshould pick replicas in turns")
+		{
+			first, second := rs.pick(), rs.pick()
+			if first == nil || second == nil || first == second {
+				t.Errorf("unexpected replicas: %p, %p", first, second)
+			}
+		}
+
+		t.Log("\ttest:1\tshould skip the replica which is down")
+		{
+			rs.list[0].markDown(time.Now())
+
+			for i := 0; i < 3; i++ {
+				if got := rs.pick(); got != rs.list[1] {
+					t.Errorf("unexpected replica: %p expected: %p", got, rs.list[1])
+				}
+			}
+		}
+
+		t.Log("\ttest:2\tshould mark unreachable replicas down")
+		{
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+
+			if err := rs.check(ctx); err == nil {
+				t.Error("expected error")
+			}
+
+			if got := rs.pick(); got != nil {
+				t.Errorf("unexpected replica: %p", got)
+			}
+		}
+
+		t.Log("\ttest:3\tshould bring replicas back after the cooldown")
+		{
+			past := time.Now().Add(-2 * replicaCooldown)
+			for _, r := range rs.list {
+				r.markDown(past)
+			}
+
+			if got := rs.pick(); got == nil {
+				t.Error("expected replica")
+			}
+		}
+	}
+}
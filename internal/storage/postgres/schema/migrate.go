@@ -1,56 +1,75 @@
 package schema
 
 import (
+	"context"
 	"database/sql"
+	"embed"
 	"fmt"
 
-	"github.com/mattes/migrate"
-	"github.com/mattes/migrate/database/postgres"
-	bindata "github.com/mattes/migrate/source/go-bindata"
+	"github.com/dipress/cards/internal/storage/migration"
+	"github.com/golang-migrate/migrate/v4/database"
+	"github.com/golang-migrate/migrate/v4/database/postgres"
 )
 
 const (
-	source          = "go-bindata"
-	database        = "postgres"
 	migrationsTable = "versions"
+	migrationsDir   = "migrations"
 )
 
-// go:generate go-bindata -prefix migrations/ -pkg schema -o migrations.bindata.go migrations/
+//go:embed migrations/*.sql
+var migrations embed.FS
 
-// Migrate migrates schema to given database connection.
-func Migrate(db *sql.DB) error {
-	m, err := newMigration(db)
-	if err != nil {
-		return fmt.Errorf("new migration: %w", err)
-	}
-
-	if err := m.Up(); err != nil {
-		return fmt.Errorf("migrate schema: %w", err)
-	}
+var schema = migration.NewSchema("postgres", migrations, migrationsDir, migrationsTable, driver)
 
-	return nil
-}
-
-func newMigration(db *sql.DB) (*migrate.Migrate, error) {
-	r := bindata.Resource(AssetNames(), Asset)
-	s, err := bindata.WithInstance(r)
+// driver runs migrations on a connection of its own, which goes back
+// to the pool on release.
+func driver(ctx context.Context, db *sql.DB, table string) (database.Driver, func() error, error) {
+	conn, err := db.Conn(ctx)
 	if err != nil {
-		return nil, fmt.Errorf("prepare source instance: %w", err)
+		return nil, nil, fmt.Errorf("conn: %w", err)
 	}
 
 	cfg := postgres.Config{
-		MigrationsTable: migrationsTable,
+		MigrationsTable: table,
 	}
 
-	d, err := postgres.WithInstance(db, &cfg)
+	d, err := postgres.WithConnection(ctx, conn, &cfg)
 	if err != nil {
-		return nil, fmt.Errorf("prepare database instance: %w", err)
+		conn.Close()
+		return nil, nil, fmt.Errorf("with connection: %w", err)
 	}
 
-	m, err := migrate.NewWithInstance(source, s, database, d)
-	if err != nil {
-		return nil, fmt.Errorf("prepare migrate instance: %w", err)
-	}
+	return d, conn.Close, nil
+}
+
+// Migrate migrates schema to given database connection.
+func Migrate(db *sql.DB) error {
+	return schema.Migrate(db)
+}
+
+// Version returns the current schema version of the database,
+// zero version means no migration was applied.
+func Version(ctx context.Context, db *sql.DB) (uint, bool, error) {
+	return schema.Version(ctx, db)
+}
+
+// Migrations returns the embedded migrations ordered by version.
+func Migrations() ([]migration.Migration, error) {
+	return schema.Migrations()
+}
+
+// ExpectedVersion returns the version of the latest migration.
+func ExpectedVersion() (uint, error) {
+	return schema.ExpectedVersion()
+}
+
+// Check returns an error when the database schema
+// isn't at the expected version.
+func Check(ctx context.Context, db *sql.DB) error {
+	return schema.Check(ctx, db)
+}
 
-	return m, nil
+// NewMigrator returns the migrator of the schema in db.
+func NewMigrator(db *sql.DB) migration.Migrator {
+	return schema.Migrator(db)
 }
@@ -1,11 +1,110 @@
 package schema
 
 import (
+	"context"
+	"database/sql"
+	"flag"
+	"fmt"
+	"io/fs"
+	"io/ioutil"
+	"strings"
 	"testing"
 
+	"github.com/dipress/cards/internal/storage/migration"
 	"github.com/stretchr/testify/assert"
 )
 
+var update = flag.Bool("update", false, "update the schema snapshot")
+
+const snapshotPath = "testdata/schema.golden"
+
+const columnsQuery = `
+	SELECT
+		c.relname,
+		a.attname,
+		format_type(a.atttypid, a.atttypmod),
+		a.attnotnull,
+		COALESCE(pg_get_expr(d.adbin, d.adrelid), '')
+	FROM pg_attribute a
+	JOIN pg_class c ON c.oid = a.attrelid
+	JOIN pg_namespace n ON n.oid = c.relnamespace
+	LEFT JOIN pg_attrdef d ON d.adrelid = a.attrelid AND d.adnum = a.attnum
+	WHERE n.nspname = current_schema()
+		AND c.relkind = 'r'
+		AND c.relname <> '` + migrationsTable + `'
+		AND a.attnum > 0
+		AND NOT a.attisdropped
+	ORDER BY c.relname, a.attnum
+`
+
+const indexesQuery = `
+	SELECT indexname, indexdef
+	FROM pg_indexes
+	WHERE schemaname = current_schema() AND tablename <> '` + migrationsTable + `'
+	ORDER BY indexname
+`
+
+// dumpSchema returns columns and indexes of the tables
+// besides the migrations table.
+func dumpSchema(t *testing.T, db *sql.DB) string {
+	var b strings.Builder
+
+	rows, err := db.Query(columnsQuery)
+	if err != nil {
+		t.Fatalf("query columns: %v", err)
+	}
+	defer rows.Close()
+
+	var table string
+	for rows.Next() {
+		var (
+			tbl, column, typ, def string
+			notNull               bool
+		)
+		if err := rows.Scan(&tbl, &column, &typ, &notNull, &def); err != nil {
+			t.Fatalf("scan column: %v", err)
+		}
+
+		if tbl != table {
+			fmt.Fprintf(&b, "table %s\n", tbl)
+			table = tbl
+		}
+
+		fmt.Fprintf(&b, "  %s %s", column, typ)
+		if notNull {
+			b.WriteString(" not null")
+		}
+		if def != "" {
+			fmt.Fprintf(&b, " default %s", def)
+		}
+		b.WriteString("\n")
+	}
+
+	if err := rows.Err(); err != nil {
+		t.Fatalf("columns: %v", err)
+	}
+
+	indexes, err := db.Query(indexesQuery)
+	if err != nil {
+		t.Fatalf("query indexes: %v", err)
+	}
+	defer indexes.Close()
+
+	for indexes.Next() {
+		var name, def string
+		if err := indexes.Scan(&name, &def); err != nil {
+			t.Fatalf("scan index: %v", err)
+		}
+		fmt.Fprintf(&b, "index %s: %s\n", name, def)
+	}
+
+	if err := indexes.Err(); err != nil {
+		t.Fatalf("indexes: %v", err)
+	}
+
+	return b.String()
+}
+
 func Test_Migrate(t *testing.T) {
 	if testing.Short() {
 		t.Skip("skipping test in short mode")
@@ -13,19 +112,61 @@ func Test_Migrate(t *testing.T) {
 
 	t.Log("with given database connection.")
 	{
-		m, err := newMigration(db)
-		assert.Nil(t, err)
-
 		t.Log("\ttest:0\tshould up schema.")
 		{
 			err := Migrate(db)
 			assert.Nil(t, err)
 		}
 
-		t.Log("\ttest:1\tshould down schema.")
+		t.Log("\ttest:1\tshould be at the expected version.")
+		{
+			err := Check(context.Background(), db)
+			assert.Nil(t, err)
+		}
+
+		t.Log("\ttest:2\tshould match the schema snapshot.")
+		{
+			got := dumpSchema(t, db)
+
+			if *update {
+				if err := ioutil.WriteFile(snapshotPath, []byte(got), 0644); err != nil {
+					t.Fatalf("write snapshot: %v", err)
+				}
+			}
+
+			expect, err := ioutil.ReadFile(snapshotPath)
+			assert.Nil(t, err)
+			assert.Equal(t, string(expect), got, "run go test -update to accept the schema changes")
+		}
+
+		t.Log("\ttest:3\tshould down schema completely.")
+		{
+			err := schema.Down(db)
+			assert.Nil(t, err)
+			assert.Equal(t, "", dumpSchema(t, db))
+		}
+
+		t.Log("\ttest:4\tshould up schema again.")
 		{
-			err := m.Down()
+			err := Migrate(db)
 			assert.Nil(t, err)
+
+			expect, err := ioutil.ReadFile(snapshotPath)
+			assert.Nil(t, err)
+			assert.Equal(t, string(expect), dumpSchema(t, db))
 		}
 	}
 }
+
+func Test_MigrationPairs(t *testing.T) {
+	names, err := fs.Glob(migrations, migrationsDir+"/*")
+	assert.Nil(t, err)
+	assert.NotEmpty(t, names)
+	assert.Nil(t, migration.CheckPairs(names))
+}
+
+func Test_ExpectedVersion(t *testing.T) {
+	version, err := ExpectedVersion()
+	assert.Nil(t, err)
+	assert.Equal(t, uint(20200305183012), version)
+}
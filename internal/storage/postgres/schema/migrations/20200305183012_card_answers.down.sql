@@ -0,0 +1,4 @@
+ALTER TABLE cards
+  DROP COLUMN IF EXISTS correct_answers,
+  DROP COLUMN IF EXISTS wrong_answers,
+  DROP COLUMN IF EXISTS answered_at;
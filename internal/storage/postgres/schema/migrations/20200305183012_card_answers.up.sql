@@ -0,0 +1,4 @@
+ALTER TABLE cards
+  ADD COLUMN correct_answers INT NOT NULL DEFAULT 0,
+  ADD COLUMN wrong_answers   INT NOT NULL DEFAULT 0,
+  ADD COLUMN answered_at     TIMESTAMP NULL;
@@ -0,0 +1,235 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+	"golang.org/x/sync/singleflight"
+)
+
+// Postgres error codes of statements which have to be prepared again.
+const (
+	invalidStatementName = "26000"
+	featureNotSupported  = "0A000"
+)
+
+// queries are prepared by Prepare, the rest are prepared on first use.
+var queries = []string{
+	createCardQuery,
+	findCardQuery,
+	listCardsQuery,
+	updateCardQuery,
+	answerCardQuery,
+	deleteCardQuery,
+}
+
+// statements caches prepared statements of a database. database/sql
+// prepares a statement again on every new connection, so statements
+// survive lost connections and live as long as the database.
+type statements struct {
+	db *sqlx.DB
+	// group makes concurrent first uses of a query prepare it once.
+	group singleflight.Group
+
+	mu    sync.RWMutex
+	stmts map[string]*sql.Stmt
+}
+
+func newStatements(db *sqlx.DB) *statements {
+	s := statements{
+		db:    db,
+		stmts: make(map[string]*sql.Stmt),
+	}
+
+	return &s
+}
+
+func (s *statements) cached(query string) (*sql.Stmt, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	stmt, ok := s.stmts[query]
+	return stmt, ok
+}
+
+// get returns the prepared statement of the query, preparing it if
+// needed. Queries are prepared without the lock, so a query waits
+// only for the first use of the same query.
+func (s *statements) get(ctx context.Context, query string) (*sql.Stmt, error) {
+	if stmt, ok := s.cached(query); ok {
+		return stmt, nil
+	}
+
+	ch := s.group.DoChan(query, func() (interface{}, error) {
+		if stmt, ok := s.cached(query); ok {
+			return stmt, nil
+		}
+
+		// The prepare is shared, a caller which gives
+		// up doesn't cancel it for the others.
+		stmt, err := s.db.PrepareContext(context.WithoutCancel(ctx), query)
+		if err != nil {
+			return nil, fmt.Errorf("prepare context: %w", err)
+		}
+
+		s.mu.Lock()
+		s.stmts[query] = stmt
+		s.mu.Unlock()
+
+		return stmt, nil
+	})
+
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	case res := <-ch:
+		if res.Err != nil {
+			return nil, res.Err
+		}
+
+		return res.Val.(*sql.Stmt), nil
+	}
+}
+
+// forget drops the statement and closes it, the next use prepares
+// the query again. Close waits for the queries which run it, a query
+// which got it before and starts later runs without it.
+func (s *statements) forget(query string, stmt *sql.Stmt) {
+	s.mu.Lock()
+	if s.stmts[query] == stmt {
+		delete(s.stmts, query)
+	}
+	s.mu.Unlock()
+
+	stmt.Close()
+}
+
+// forgotten tells whether the statement was dropped from the cache.
+func (s *statements) forgotten(query string, stmt *sql.Stmt) bool {
+	cur, ok := s.cached(query)
+	return !ok || cur != stmt
+}
+
+func (s *statements) close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var errs []string
+	for query, stmt := range s.stmts {
+		if err := stmt.Close(); err != nil {
+			errs = append(errs, err.Error())
+		}
+		delete(s.stmts, query)
+	}
+
+	if len(errs) > 0 {
+		return errors.New(strings.Join(errs, "; "))
+	}
+
+	return nil
+}
+
+// stale tells whether the server lost the statement or
+// its plan no longer fits the schema.
+func stale(err error) bool {
+	var pqErr *pq.Error
+	if !errors.As(err, &pqErr) {
+		return false
+	}
+
+	return pqErr.Code == invalidStatementName || pqErr.Code == featureNotSupported
+}
+
+// prepared runs queries with the cached statements, a query falls
+// back to q when it can't be prepared. Statements are bound to tx
+// when it isn't nil.
+type prepared struct {
+	q     querier
+	tx    *sqlx.Tx
+	stmts *statements
+}
+
+func (p prepared) stmt(ctx context.Context, query string) (*sql.Stmt, bool) {
+	if p.stmts == nil {
+		return nil, false
+	}
+
+	stmt, err := p.stmts.get(ctx, query)
+	if err != nil {
+		return nil, false
+	}
+
+	return stmt, true
+}
+
+func (p prepared) bind(ctx context.Context, stmt *sql.Stmt) *sql.Stmt {
+	if p.tx != nil {
+		return p.tx.StmtContext(ctx, stmt)
+	}
+
+	return stmt
+}
+
+// retry forgets a stale statement, the query runs again without it
+// unless the failure aborted the transaction. A statement forgotten by
+// another query is closed, the query runs again without it as well.
+func (p prepared) retry(query string, stmt *sql.Stmt, err error) bool {
+	if err == nil {
+		return false
+	}
+
+	if stale(err) {
+		p.stmts.forget(query, stmt)
+		return p.tx == nil
+	}
+
+	return p.stmts.forgotten(query, stmt)
+}
+
+func (p prepared) ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error) {
+	stmt, ok := p.stmt(ctx, query)
+	if !ok {
+		return p.q.ExecContext(ctx, query, args...)
+	}
+
+	res, err := p.bind(ctx, stmt).ExecContext(ctx, args...)
+	if p.retry(query, stmt, err) {
+		return p.q.ExecContext(ctx, query, args...)
+	}
+
+	return res, err
+}
+
+func (p prepared) QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error) {
+	stmt, ok := p.stmt(ctx, query)
+	if !ok {
+		return p.q.QueryContext(ctx, query, args...)
+	}
+
+	rows, err := p.bind(ctx, stmt).QueryContext(ctx, args...)
+	if p.retry(query, stmt, err) {
+		return p.q.QueryContext(ctx, query, args...)
+	}
+
+	return rows, err
+}
+
+func (p prepared) QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row {
+	stmt, ok := p.stmt(ctx, query)
+	if !ok {
+		return p.q.QueryRowContext(ctx, query, args...)
+	}
+
+	row := p.bind(ctx, stmt).QueryRowContext(ctx, args...)
+	if p.retry(query, stmt, row.Err()) {
+		return p.q.QueryRowContext(ctx, query, args...)
+	}
+
+	return row
+}
@@ -0,0 +1,137 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+)
+
+func TestStale(t *testing.T) {
+	tests := []struct {
+		name   string
+		err    error
+		expect bool
+	}{
+		{
+			name:   "lost statement",
+			err:    fmt.Errorf("query: %w", &pq.Error{Code: invalidStatementName}),
+			expect: true,
+		},
+		{
+			name:   "changed plan",
+			err:    &pq.Error{Code: featureNotSupported},
+			expect: true,
+		},
+		{
+			name: "unique violation",
+			err:  &pq.Error{Code: "23505"},
+		},
+		{
+			name: "no rows",
+			err:  sql.ErrNoRows,
+		},
+		{
+			name: "nil",
+		},
+	}
+
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			if got := stale(tc.err); got != tc.expect {
+				t.Errorf("unexpected stale: %t expected: %t", got, tc.expect)
+			}
+		})
+	}
+}
+
+func TestStatements(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping test in short mode")
+	}
+
+	t.Log("with initialized statements")
+	{
+		db, teardown := postgresDB(t)
+		defer teardown()
+
+		s := newStatements(sqlx.NewDb(db, driverName))
+		defer s.close()
+
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		const query = "SELECT 1"
+
+		t.Log("\ttest:0\tshould prepare a query once for concurrent uses")
+		{
+			const uses = 10
+
+			var wg sync.WaitGroup
+			stmts := make([]*sql.Stmt, uses)
+			errs := make([]error, uses)
+			for i := 0; i < uses; i++ {
+				wg.Add(1)
+				go func(i int) {
+					defer wg.Done()
+					stmts[i], errs[i] = s.get(ctx, query)
+				}(i)
+			}
+			wg.Wait()
+
+			for i := range stmts {
+				if errs[i] != nil {
+					t.Errorf("unexpected error: %v", errs[i])
+				}
+
+				if stmts[i] != stmts[0] {
+					t.Errorf("unexpected statement: %p expected: %p", stmts[i], stmts[0])
+				}
+			}
+		}
+
+		t.Log("\ttest:1\tshould close a forgotten statement")
+		{
+			stmt, err := s.get(ctx, query)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			s.forget(query, stmt)
+
+			if _, err := stmt.ExecContext(ctx); err == nil {
+				t.Error("expected error on a closed statement")
+			}
+
+			if !s.forgotten(query, stmt) {
+				t.Error("expected the statement to be forgotten")
+			}
+
+			fresh, err := s.get(ctx, query)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if fresh == stmt {
+				t.Error("expected the query to be prepared again")
+			}
+		}
+
+		t.Log("\ttest:2\tshould stop waiting when the context is canceled")
+		{
+			canceled, cancel := context.WithCancel(ctx)
+			cancel()
+
+			if _, err := s.get(canceled, "SELECT 2"); err != context.Canceled {
+				t.Errorf("unexpected error: %v expected: %v", err, context.Canceled)
+			}
+		}
+	}
+}
@@ -0,0 +1,108 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/dipress/cards/internal/card"
+)
+
+func TestTransactor(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping test in short mode")
+	}
+
+	t.Log("with initialized transactor and repository")
+	{
+		db, teardown := postgresDB(t)
+		defer teardown()
+
+		r := NewCardRepository(db)
+		tx := NewTransactor(db)
+
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		nc := card.NewCard{
+			UserID:        12,
+			Word:          "exceed",
+			Transcription: "ikˈsēd",
+			Translation:   "превышать",
+		}
+
+		t.Log("\ttest:0\tshould commit when the function succeeds")
+		{
+			err := tx.WithinTransaction(ctx, func(ctx context.Context) error {
+				var cd card.Card
+				return r.Create(ctx, &nc, &cd)
+			})
+			if err != nil {
+				t.Errorf("unexpected error: %v", err)
+			}
+
+			cards, err := r.List(ctx, nc.UserID)
+			if err != nil {
+				t.Errorf("unexpected error: %v", err)
+			}
+
+			if len(cards) != 1 {
+				t.Errorf("unexpected cards count: %d expected: %d", len(cards), 1)
+			}
+		}
+
+		t.Log("\ttest:1\tshould roll back when the function fails")
+		{
+			errFailed := errors.New("failed")
+
+			err := tx.WithinTransaction(ctx, func(ctx context.Context) error {
+				var cd card.Card
+				if err := r.Create(ctx, &nc, &cd); err != nil {
+					return err
+				}
+
+				return errFailed
+			})
+			if !errors.Is(err, errFailed) {
+				t.Errorf("unexpected error: %v expected: %v", err, errFailed)
+			}
+
+			cards, err := r.List(ctx, nc.UserID)
+			if err != nil {
+				t.Errorf("unexpected error: %v", err)
+			}
+
+			if len(cards) != 1 {
+				t.Errorf("unexpected cards count: %d expected: %d", len(cards), 1)
+			}
+		}
+
+		t.Log("\ttest:2\tshould join the outer transaction")
+		{
+			errFailed := errors.New("failed")
+
+			err := tx.WithinTransaction(ctx, func(ctx context.Context) error {
+				if err := tx.WithinTransaction(ctx, func(ctx context.Context) error {
+					var cd card.Card
+					return r.Create(ctx, &nc, &cd)
+				}); err != nil {
+					return err
+				}
+
+				return errFailed
+			})
+			if !errors.Is(err, errFailed) {
+				t.Errorf("unexpected error: %v expected: %v", err, errFailed)
+			}
+
+			cards, err := r.List(ctx, nc.UserID)
+			if err != nil {
+				t.Errorf("unexpected error: %v", err)
+			}
+
+			if len(cards) != 1 {
+				t.Errorf("unexpected cards count: %d expected: %d", len(cards), 1)
+			}
+		}
+	}
+}
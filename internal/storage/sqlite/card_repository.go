@@ -0,0 +1,212 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/dipress/cards/internal/card"
+)
+
+// CardRepository holds CRUD actions, they run in
+// the transaction of the context if there is one.
+type CardRepository struct {
+	db *sql.DB
+}
+
+// NewCardRepository factory prepares the card repository to work.
+func NewCardRepository(db *sql.DB) *CardRepository {
+	r := CardRepository{
+		db: db,
+	}
+
+	return &r
+}
+
+// cardColumns lists columns in the order scanCard expects.
+const cardColumns = `
+	id, user_id, word, transcription, translation,
+	correct_answers, wrong_answers, answered_at, created_at, updated_at
+`
+
+type scanner interface {
+	Scan(dest ...interface{}) error
+}
+
+func scanCard(s scanner, cd *card.Card) error {
+	return s.Scan(
+		&cd.ID,
+		&cd.UserID,
+		&cd.Word,
+		&cd.Transcription,
+		&cd.Translation,
+		&cd.CorrectAnswers,
+		&cd.WrongAnswers,
+		&cd.AnsweredAt,
+		&cd.CreatedAt,
+		&cd.UpdatedAt,
+	)
+}
+
+const createCardQuery = `
+	INSERT INTO cards (word, transcription, translation, user_id)
+	VALUES (?, ?, ?, ?)
+	RETURNING ` + cardColumns
+
+// Create inserts a new card into the database.
+func (r *CardRepository) Create(ctx context.Context, f *card.NewCard, ca *card.Card) (err error) {
+	ctx, span := startSpan(ctx, "INSERT", createCardQuery)
+	defer func() { endSpan(span, err) }()
+
+	row := conn(ctx, r.db).QueryRowContext(ctx, createCardQuery, f.Word, f.Transcription, f.Translation, f.UserID)
+	if err := scanCard(row, ca); err != nil {
+		return fmt.Errorf("query context scan: %w", err)
+	}
+
+	return nil
+}
+
+const findCardQuery = `SELECT ` + cardColumns + ` FROM cards WHERE id = ?`
+
+// Find finds a card by id.
+func (r *CardRepository) Find(ctx context.Context, id int) (_ *card.Card, err error) {
+	ctx, span := startSpan(ctx, "SELECT", findCardQuery)
+	defer func() { endSpan(span, err) }()
+
+	var cd card.Card
+
+	if err := scanCard(conn(ctx, r.db).QueryRowContext(ctx, findCardQuery, id), &cd); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, card.ErrNotFound
+		}
+
+		return nil, fmt.Errorf("query row scan: %w", err)
+	}
+
+	return &cd, nil
+}
+
+const listCardsQuery = `
+	SELECT ` + cardColumns + `
+	FROM cards
+	WHERE user_id = ?
+	ORDER BY id
+`
+
+// List lists cards by user id.
+func (r *CardRepository) List(ctx context.Context, userID int) (_ []card.Card, err error) {
+	ctx, span := startSpan(ctx, "SELECT", listCardsQuery)
+	defer func() { endSpan(span, err) }()
+
+	rows, err := conn(ctx, r.db).QueryContext(ctx, listCardsQuery, userID)
+	if err != nil {
+		return nil, fmt.Errorf("query context: %w", err)
+	}
+	defer rows.Close()
+
+	cards := make([]card.Card, 0)
+	for rows.Next() {
+		var cd card.Card
+		if err := scanCard(rows, &cd); err != nil {
+			return nil, fmt.Errorf("rows scan: %w", err)
+		}
+
+		cards = append(cards, cd)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows: %w", err)
+	}
+
+	return cards, nil
+}
+
+const updateCardQuery = `
+	UPDATE
+		cards
+	SET
+		user_id=?,
+		word=?,
+		transcription=?,
+		translation=?,
+		updated_at=CURRENT_TIMESTAMP
+	WHERE
+		id=?
+	RETURNING ` + cardColumns
+
+// Update updates a card by id and fills it with the updated row.
+func (r *CardRepository) Update(ctx context.Context, id int, ca *card.Card) (err error) {
+	ctx, span := startSpan(ctx, "UPDATE", updateCardQuery)
+	defer func() { endSpan(span, err) }()
+
+	row := conn(ctx, r.db).QueryRowContext(ctx, updateCardQuery, ca.UserID, ca.Word, ca.Transcription, ca.Translation, id)
+	if err := scanCard(row, ca); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return card.ErrNotFound
+		}
+
+		return fmt.Errorf("query row scan: %w", err)
+	}
+
+	return nil
+}
+
+const answerCardQuery = `
+	UPDATE
+		cards
+	SET
+		correct_answers=correct_answers + ?,
+		wrong_answers=wrong_answers + ?,
+		answered_at=CURRENT_TIMESTAMP
+	WHERE
+		id=?
+	RETURNING ` + cardColumns
+
+// Answer counts the learner's answer on a card by id.
+func (r *CardRepository) Answer(ctx context.Context, id int, correct bool) (_ *card.Card, err error) {
+	ctx, span := startSpan(ctx, "UPDATE", answerCardQuery)
+	defer func() { endSpan(span, err) }()
+
+	var right, wrong int
+	if correct {
+		right = 1
+	} else {
+		wrong = 1
+	}
+
+	var cd card.Card
+	if err := scanCard(conn(ctx, r.db).QueryRowContext(ctx, answerCardQuery, right, wrong, id), &cd); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, card.ErrNotFound
+		}
+
+		return nil, fmt.Errorf("query row scan: %w", err)
+	}
+
+	return &cd, nil
+}
+
+const deleteCardQuery = `DELETE FROM cards WHERE id=?`
+
+// Delete deletes a card by id
+func (r *CardRepository) Delete(ctx context.Context, id int) (err error) {
+	ctx, span := startSpan(ctx, "DELETE", deleteCardQuery)
+	defer func() { endSpan(span, err) }()
+
+	res, err := conn(ctx, r.db).ExecContext(ctx, deleteCardQuery, id)
+	if err != nil {
+		return fmt.Errorf("exec context: %w", err)
+	}
+
+	n, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("rows affected: %w", err)
+	}
+
+	if n == 0 {
+		return card.ErrNotFound
+	}
+
+	return nil
+}
@@ -0,0 +1,15 @@
+package sqlite
+
+import (
+	"testing"
+
+	"github.com/dipress/cards/internal/card"
+	"github.com/dipress/cards/internal/storage/storagetest"
+)
+
+func TestCardRepository(t *testing.T) {
+	storagetest.TestCardRepository(t, func(t *testing.T) (card.Repository, func()) {
+		db, teardown := sqliteDB(t)
+		return NewCardRepository(db), func() { teardown() }
+	})
+}
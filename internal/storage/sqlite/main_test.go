@@ -0,0 +1,24 @@
+package sqlite
+
+import (
+	"database/sql"
+	"path/filepath"
+	"testing"
+
+	"github.com/dipress/cards/internal/storage/sqlite/schema"
+)
+
+// sqliteDB opens a migrated database in a temporary file.
+func sqliteDB(t *testing.T) (db *sql.DB, teardown func() error) {
+	db, err := Open(filepath.Join(t.TempDir(), "cards.db"))
+	if err != nil {
+		t.Fatalf("open sqlite connection: %s", err)
+	}
+
+	if err := schema.Migrate(db); err != nil {
+		db.Close()
+		t.Fatalf("migrate schema: %s", err)
+	}
+
+	return db, db.Close
+}
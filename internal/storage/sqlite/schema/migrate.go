@@ -0,0 +1,69 @@
+package schema
+
+import (
+	"context"
+	"database/sql"
+	"embed"
+	"fmt"
+
+	"github.com/dipress/cards/internal/storage/migration"
+	"github.com/golang-migrate/migrate/v4/database"
+	"github.com/golang-migrate/migrate/v4/database/sqlite3"
+)
+
+const (
+	migrationsTable = "versions"
+	migrationsDir   = "migrations"
+)
+
+//go:embed migrations/*.sql
+var migrations embed.FS
+
+var schema = migration.NewSchema("sqlite3", migrations, migrationsDir, migrationsTable, driver)
+
+// driver runs migrations on the pool of db, the
+// sqlite3 driver holds no connection to release.
+func driver(ctx context.Context, db *sql.DB, table string) (database.Driver, func() error, error) {
+	cfg := sqlite3.Config{
+		MigrationsTable: table,
+	}
+
+	d, err := sqlite3.WithInstance(db, &cfg)
+	if err != nil {
+		return nil, nil, fmt.Errorf("with instance: %w", err)
+	}
+
+	return d, func() error { return nil }, nil
+}
+
+// Migrate migrates schema to given database connection.
+func Migrate(db *sql.DB) error {
+	return schema.Migrate(db)
+}
+
+// Version returns the current schema version of the database,
+// zero version means no migration was applied.
+func Version(ctx context.Context, db *sql.DB) (uint, bool, error) {
+	return schema.Version(ctx, db)
+}
+
+// Migrations returns the embedded migrations ordered by version.
+func Migrations() ([]migration.Migration, error) {
+	return schema.Migrations()
+}
+
+// ExpectedVersion returns the version of the latest migration.
+func ExpectedVersion() (uint, error) {
+	return schema.ExpectedVersion()
+}
+
+// Check returns an error when the database schema
+// isn't at the expected version.
+func Check(ctx context.Context, db *sql.DB) error {
+	return schema.Check(ctx, db)
+}
+
+// NewMigrator returns the migrator of the schema in db.
+func NewMigrator(db *sql.DB) migration.Migrator {
+	return schema.Migrator(db)
+}
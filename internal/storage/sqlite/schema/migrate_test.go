@@ -0,0 +1,118 @@
+package schema
+
+import (
+	"context"
+	"database/sql"
+	"flag"
+	"fmt"
+	"io/fs"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/dipress/cards/internal/storage/migration"
+	_ "github.com/mattn/go-sqlite3"
+	"github.com/stretchr/testify/assert"
+)
+
+var update = flag.Bool("update", false, "update the schema snapshot")
+
+const snapshotPath = "testdata/schema.golden"
+
+const dumpQuery = `
+	SELECT type, name, sql
+	FROM sqlite_master
+	WHERE tbl_name NOT LIKE 'sqlite_%' AND tbl_name <> '` + migrationsTable + `' AND sql IS NOT NULL
+	ORDER BY type, name
+`
+
+// dumpSchema returns tables and indexes of the database besides the migrations table.
+func dumpSchema(t *testing.T, db *sql.DB) string {
+	rows, err := db.Query(dumpQuery)
+	if err != nil {
+		t.Fatalf("query schema: %v", err)
+	}
+	defer rows.Close()
+
+	var b strings.Builder
+	for rows.Next() {
+		var typ, name, stmt string
+		if err := rows.Scan(&typ, &name, &stmt); err != nil {
+			t.Fatalf("scan schema: %v", err)
+		}
+		fmt.Fprintf(&b, "-- %s %s\n%s;\n\n", typ, name, stmt)
+	}
+
+	if err := rows.Err(); err != nil {
+		t.Fatalf("rows: %v", err)
+	}
+
+	return b.String()
+}
+
+func Test_Migrate(t *testing.T) {
+	t.Log("with a fresh database.")
+	{
+		db, err := sql.Open("sqlite3", filepath.Join(t.TempDir(), "cards.db"))
+		assert.Nil(t, err)
+		defer db.Close()
+
+		t.Log("\ttest:0\tshould up schema.")
+		{
+			err := Migrate(db)
+			assert.Nil(t, err)
+		}
+
+		t.Log("\ttest:1\tshould be at the expected version.")
+		{
+			err := Check(context.Background(), db)
+			assert.Nil(t, err)
+		}
+
+		t.Log("\ttest:2\tshould match the schema snapshot.")
+		{
+			got := dumpSchema(t, db)
+
+			if *update {
+				if err := ioutil.WriteFile(snapshotPath, []byte(got), 0644); err != nil {
+					t.Fatalf("write snapshot: %v", err)
+				}
+			}
+
+			expect, err := ioutil.ReadFile(snapshotPath)
+			assert.Nil(t, err)
+			assert.Equal(t, string(expect), got, "run go test -update to accept the schema changes")
+		}
+
+		t.Log("\ttest:3\tshould down schema completely.")
+		{
+			err := schema.Down(db)
+			assert.Nil(t, err)
+			assert.Equal(t, "", dumpSchema(t, db))
+		}
+
+		t.Log("\ttest:4\tshould up schema again.")
+		{
+			err := Migrate(db)
+			assert.Nil(t, err)
+
+			expect, err := ioutil.ReadFile(snapshotPath)
+			assert.Nil(t, err)
+			assert.Equal(t, string(expect), dumpSchema(t, db))
+		}
+	}
+}
+
+func Test_MigrationPairs(t *testing.T) {
+	names, err := fs.Glob(migrations, migrationsDir+"/*")
+	assert.Nil(t, err)
+	assert.NotEmpty(t, names)
+	assert.Nil(t, migration.CheckPairs(names))
+}
+
+func Test_ExpectedVersion(t *testing.T) {
+	version, err := ExpectedVersion()
+	assert.Nil(t, err)
+	assert.Equal(t, uint(20200305183012), version)
+}
@@ -0,0 +1,11 @@
+CREATE TABLE IF NOT EXISTS cards (
+  id            INTEGER PRIMARY KEY AUTOINCREMENT,
+  user_id       INTEGER NOT NULL,
+  word          VARCHAR(255) NOT NULL,
+  transcription VARCHAR(255) NOT NULL,
+  translation   VARCHAR(255) NOT NULL,
+
+  /* timestamp */
+  created_at    TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  updated_at    TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
@@ -0,0 +1,3 @@
+ALTER TABLE cards DROP COLUMN answered_at;
+ALTER TABLE cards DROP COLUMN wrong_answers;
+ALTER TABLE cards DROP COLUMN correct_answers;
@@ -0,0 +1,3 @@
+ALTER TABLE cards ADD COLUMN correct_answers INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE cards ADD COLUMN wrong_answers INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE cards ADD COLUMN answered_at TIMESTAMP NULL;
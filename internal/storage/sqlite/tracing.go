@@ -0,0 +1,20 @@
+package sqlite
+
+import (
+	"context"
+
+	"github.com/dipress/cards/internal/storage/sqltrace"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const tableName = "cards"
+
+var tracer = sqltrace.New("github.com/dipress/cards/internal/storage/sqlite", "sqlite", tableName)
+
+func startSpan(ctx context.Context, operation, query string) (context.Context, trace.Span) {
+	return tracer.Start(ctx, operation, query)
+}
+
+func endSpan(span trace.Span, err error) {
+	sqltrace.End(span, err)
+}
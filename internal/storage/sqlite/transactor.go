@@ -0,0 +1,79 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/dipress/cards/internal/card"
+)
+
+type txKey struct{}
+
+// querier is implemented by both the database and a transaction.
+type querier interface {
+	ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
+	QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error)
+	QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row
+}
+
+// conn returns the transaction of the context if there is one.
+func conn(ctx context.Context, db *sql.DB) querier {
+	if tx, ok := ctx.Value(txKey{}).(*sql.Tx); ok {
+		return tx
+	}
+
+	return db
+}
+
+// Transactor runs functions in SQLite transactions.
+type Transactor struct {
+	db *sql.DB
+}
+
+// NewTransactor factory prepares the transactor to work.
+func NewTransactor(db *sql.DB) *Transactor {
+	t := Transactor{
+		db: db,
+	}
+
+	return &t
+}
+
+// WithinTransaction commits the transaction if fn succeeds and rolls
+// it back otherwise, nested calls join the outer transaction.
+func (t *Transactor) WithinTransaction(ctx context.Context, fn func(ctx context.Context) error) (err error) {
+	if _, ok := ctx.Value(txKey{}).(*sql.Tx); ok {
+		return fn(ctx)
+	}
+
+	tx, err := t.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("begin: %w", err)
+	}
+
+	defer func() {
+		if p := recover(); p != nil {
+			tx.Rollback()
+			panic(p)
+		}
+	}()
+
+	txCtx, committed := card.ContextWithTransaction(context.WithValue(ctx, txKey{}, tx))
+
+	if err := fn(txCtx); err != nil {
+		if rErr := tx.Rollback(); rErr != nil {
+			return fmt.Errorf("rollback: %v: %w", rErr, err)
+		}
+
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("commit: %w", err)
+	}
+
+	committed()
+
+	return nil
+}
@@ -0,0 +1,53 @@
+package sqltrace
+
+import (
+	"context"
+	"strings"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Tracer starts client spans of SQL queries to a table.
+type Tracer struct {
+	tracer trace.Tracer
+	system string
+	table  string
+}
+
+// New factory prepares the tracer, name is the instrumenting package
+// and system is the db.system attribute like postgresql.
+func New(name, system, table string) *Tracer {
+	t := Tracer{
+		tracer: otel.Tracer(name),
+		system: system,
+		table:  table,
+	}
+
+	return &t
+}
+
+// Start starts a span for the SQL query,
+// operation is the SQL command like SELECT.
+func (t *Tracer) Start(ctx context.Context, operation, query string) (context.Context, trace.Span) {
+	return t.tracer.Start(ctx, operation+" "+t.table,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(
+			attribute.String("db.system", t.system),
+			attribute.String("db.operation.name", operation),
+			attribute.String("db.collection.name", t.table),
+			attribute.String("db.query.text", strings.Join(strings.Fields(query), " ")),
+		),
+	)
+}
+
+// End records err on the span and ends it.
+func End(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
@@ -0,0 +1,34 @@
+package sqltrace
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func TestTracer(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+
+	tracer := New("test", "sqlite", "cards")
+
+	_, span := tracer.Start(context.Background(), "SELECT", "SELECT *\n\tFROM cards\n\tWHERE id = ?")
+	End(span, errors.New("mock error"))
+
+	spans := recorder.Ended()
+	if !assert.Len(t, spans, 1) {
+		return
+	}
+
+	assert.Equal(t, "SELECT cards", spans[0].Name())
+	assert.Equal(t, codes.Error, spans[0].Status().Code)
+	assert.Contains(t, spans[0].Attributes(), attribute.String("db.system", "sqlite"))
+	assert.Contains(t, spans[0].Attributes(), attribute.String("db.query.text", "SELECT * FROM cards WHERE id = ?"))
+}
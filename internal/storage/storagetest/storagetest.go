@@ -0,0 +1,431 @@
+// Package storagetest implements a conformance test suite
+// for card.Repository implementations, a backend passes it
+// to prove it behaves the same as the ones of this repository.
+package storagetest
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/dipress/cards/internal/card"
+)
+
+const caseTimeout = 5 * time.Second
+
+// Factory returns an empty repository and its teardown.
+type Factory func(t *testing.T) (card.Repository, func())
+
+// TestCardRepository runs the suite against repositories made by newRepo,
+// every case gets a new one.
+func TestCardRepository(t *testing.T, newRepo Factory) {
+	cases := []struct {
+		name string
+		test func(t *testing.T, r card.Repository)
+	}{
+		{"create", testCreate},
+		{"find", testFind},
+		{"list", testList},
+		{"update", testUpdate},
+		{"answer", testAnswer},
+		{"delete", testDelete},
+		{"timestamps", testTimestamps},
+		{"concurrent writers", testConcurrentWriters},
+		{"cancelled context", testCancelledContext},
+	}
+
+	for _, tc := range cases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			r, teardown := newRepo(t)
+			defer teardown()
+
+			tc.test(t, r)
+		})
+	}
+}
+
+func newCard(userID int, word string) card.NewCard {
+	return card.NewCard{
+		UserID:        userID,
+		Word:          word,
+		Transcription: "-",
+		Translation:   "-",
+	}
+}
+
+func create(ctx context.Context, t *testing.T, r card.Repository, nc card.NewCard) card.Card {
+	t.Helper()
+
+	var cd card.Card
+	if err := r.Create(ctx, &nc, &cd); err != nil {
+		t.Fatalf("create card: %v", err)
+	}
+
+	return cd
+}
+
+func testCreate(t *testing.T, r card.Repository) {
+	ctx, cancel := context.WithTimeout(context.Background(), caseTimeout)
+	defer cancel()
+
+	t.Log("\ttest:0\tshould assign ids and timestamps")
+	{
+		first := create(ctx, t, r, newCard(1, "exceed"))
+		second := create(ctx, t, r, newCard(1, "grow"))
+
+		if first.ID == 0 || second.ID <= first.ID {
+			t.Errorf("unexpected ids: %d, %d", first.ID, second.ID)
+		}
+
+		if first.CreatedAt.IsZero() || !first.UpdatedAt.Equal(first.CreatedAt) {
+			t.Errorf("unexpected timestamps: %v, %v", first.CreatedAt, first.UpdatedAt)
+		}
+
+		if first.AnsweredAt != nil || first.CorrectAnswers != 0 || first.WrongAnswers != 0 {
+			t.Errorf("unexpected answers: %+v", first)
+		}
+
+		if first.Word != "exceed" || first.UserID != 1 {
+			t.Errorf("unexpected card: %+v", first)
+		}
+	}
+}
+
+func testFind(t *testing.T, r card.Repository) {
+	ctx, cancel := context.WithTimeout(context.Background(), caseTimeout)
+	defer cancel()
+
+	cd := create(ctx, t, r, newCard(2, "exceed"))
+
+	t.Log("\ttest:0\tshould find the card")
+	{
+		got, err := r.Find(ctx, cd.ID)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if got.ID != cd.ID || got.Word != cd.Word || !got.CreatedAt.Equal(cd.CreatedAt) {
+			t.Errorf("unexpected card: %+v expected: %+v", got, cd)
+		}
+	}
+
+	t.Log("\ttest:1\tshould get a not found error")
+	{
+		if _, err := r.Find(ctx, cd.ID+1); !errors.Is(err, card.ErrNotFound) {
+			t.Errorf("unexpected error: %v expected: %v", err, card.ErrNotFound)
+		}
+	}
+}
+
+func testList(t *testing.T, r card.Repository) {
+	ctx, cancel := context.WithTimeout(context.Background(), caseTimeout)
+	defer cancel()
+
+	first := create(ctx, t, r, newCard(5, "exceed"))
+	create(ctx, t, r, newCard(6, "depict"))
+	second := create(ctx, t, r, newCard(5, "grow"))
+
+	t.Log("\ttest:0\tshould list the user cards ordered by id")
+	{
+		cards, err := r.List(ctx, 5)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if len(cards) != 2 || cards[0].ID != first.ID || cards[1].ID != second.ID {
+			t.Errorf("unexpected cards: %+v", cards)
+		}
+	}
+
+	t.Log("\ttest:1\tshould list no cards of unknown user")
+	{
+		cards, err := r.List(ctx, 7)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if cards == nil || len(cards) != 0 {
+			t.Errorf("unexpected cards: %#v", cards)
+		}
+	}
+}
+
+func testUpdate(t *testing.T, r card.Repository) {
+	ctx, cancel := context.WithTimeout(context.Background(), caseTimeout)
+	defer cancel()
+
+	cd := create(ctx, t, r, newCard(3, "grow"))
+
+	t.Log("\ttest:0\tshould update the card")
+	{
+		updated := card.Card{
+			UserID:        cd.UserID,
+			Word:          "climb",
+			Transcription: "klīm",
+			Translation:   "взбираться",
+		}
+
+		if err := r.Update(ctx, cd.ID, &updated); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		got, err := r.Find(ctx, cd.ID)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if got.Word != "climb" || got.Transcription != "klīm" || got.Translation != "взбираться" {
+			t.Errorf("unexpected card: %+v", got)
+		}
+
+		if updated.ID != cd.ID || !updated.CreatedAt.Equal(cd.CreatedAt) || !updated.UpdatedAt.Equal(got.UpdatedAt) {
+			t.Errorf("expected to fill the updated card: %+v stored: %+v", updated, got)
+		}
+	}
+
+	t.Log("\ttest:1\tshould get a not found error")
+	{
+		if err := r.Update(ctx, cd.ID+1, &cd); !errors.Is(err, card.ErrNotFound) {
+			t.Errorf("unexpected error: %v expected: %v", err, card.ErrNotFound)
+		}
+	}
+}
+
+func testAnswer(t *testing.T, r card.Repository) {
+	ctx, cancel := context.WithTimeout(context.Background(), caseTimeout)
+	defer cancel()
+
+	cd := create(ctx, t, r, newCard(6, "answer"))
+
+	t.Log("\ttest:0\tshould count answers on the card")
+	{
+		if _, err := r.Answer(ctx, cd.ID, true); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		got, err := r.Answer(ctx, cd.ID, false)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if got.CorrectAnswers != 1 || got.WrongAnswers != 1 || got.AnsweredAt == nil {
+			t.Errorf("unexpected answers: %+v", got)
+		}
+	}
+
+	t.Log("\ttest:1\tshould get a not found error")
+	{
+		if _, err := r.Answer(ctx, cd.ID+1, true); !errors.Is(err, card.ErrNotFound) {
+			t.Errorf("unexpected error: %v expected: %v", err, card.ErrNotFound)
+		}
+	}
+}
+
+func testDelete(t *testing.T, r card.Repository) {
+	ctx, cancel := context.WithTimeout(context.Background(), caseTimeout)
+	defer cancel()
+
+	cd := create(ctx, t, r, newCard(4, "spread"))
+
+	t.Log("\ttest:0\tshould delete the card")
+	{
+		if err := r.Delete(ctx, cd.ID); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if _, err := r.Find(ctx, cd.ID); !errors.Is(err, card.ErrNotFound) {
+			t.Errorf("unexpected error: %v expected: %v", err, card.ErrNotFound)
+		}
+	}
+
+	t.Log("\ttest:1\tshould get a not found error")
+	{
+		if err := r.Delete(ctx, cd.ID); !errors.Is(err, card.ErrNotFound) {
+			t.Errorf("unexpected error: %v expected: %v", err, card.ErrNotFound)
+		}
+	}
+}
+
+func testTimestamps(t *testing.T, r card.Repository) {
+	ctx, cancel := context.WithTimeout(context.Background(), caseTimeout)
+	defer cancel()
+
+	cd := create(ctx, t, r, newCard(8, "grow"))
+
+	t.Log("\ttest:0\tshould keep created_at and move updated_at on update")
+	{
+		cd.Word = "climb"
+		if err := r.Update(ctx, cd.ID, &cd); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		got, err := r.Find(ctx, cd.ID)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if !got.CreatedAt.Equal(cd.CreatedAt) {
+			t.Errorf("unexpected created_at: %v expected: %v", got.CreatedAt, cd.CreatedAt)
+		}
+
+		if got.UpdatedAt.Before(cd.UpdatedAt) {
+			t.Errorf("unexpected updated_at: %v before %v", got.UpdatedAt, cd.UpdatedAt)
+		}
+	}
+
+	t.Log("\ttest:1\tshould set answered_at on answer only")
+	{
+		got, err := r.Find(ctx, cd.ID)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if got.AnsweredAt != nil {
+			t.Errorf("unexpected answered_at: %v", got.AnsweredAt)
+		}
+
+		answered, err := r.Answer(ctx, cd.ID, true)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if answered.AnsweredAt == nil || answered.AnsweredAt.Before(cd.CreatedAt) {
+			t.Errorf("unexpected answered_at: %v", answered.AnsweredAt)
+		}
+	}
+}
+
+func testConcurrentWriters(t *testing.T, r card.Repository) {
+	const (
+		writers = 8
+		writes  = 10
+	)
+
+	ctx, cancel := context.WithTimeout(context.Background(), caseTimeout)
+	defer cancel()
+
+	cd := create(ctx, t, r, newCard(9, "answer"))
+
+	var (
+		wg   sync.WaitGroup
+		mu   sync.Mutex
+		ids  = make(map[int]bool)
+		errs = make(chan error, writers*writes*2)
+	)
+
+	for i := 0; i < writers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			for j := 0; j < writes; j++ {
+				nc := newCard(10, "exceed")
+
+				var created card.Card
+				if err := r.Create(ctx, &nc, &created); err != nil {
+					errs <- err
+					continue
+				}
+
+				mu.Lock()
+				ids[created.ID] = true
+				mu.Unlock()
+
+				if _, err := r.Answer(ctx, cd.ID, true); err != nil {
+					errs <- err
+				}
+			}
+		}()
+	}
+
+	wg.Wait()
+	close(errs)
+
+	t.Log("\ttest:0\tshould not fail")
+	{
+		for err := range errs {
+			t.Errorf("unexpected error: %v", err)
+		}
+	}
+
+	t.Log("\ttest:1\tshould assign unique ids")
+	{
+		if len(ids) != writers*writes {
+			t.Errorf("unexpected unique ids: %d expected: %d", len(ids), writers*writes)
+		}
+
+		cards, err := r.List(ctx, 10)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if len(cards) != writers*writes {
+			t.Errorf("unexpected cards count: %d expected: %d", len(cards), writers*writes)
+		}
+	}
+
+	t.Log("\ttest:2\tshould count every answer")
+	{
+		got, err := r.Find(ctx, cd.ID)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if got.CorrectAnswers != writers*writes {
+			t.Errorf("unexpected correct answers: %d expected: %d", got.CorrectAnswers, writers*writes)
+		}
+	}
+}
+
+func testCancelledContext(t *testing.T, r card.Repository) {
+	ctx, cancel := context.WithTimeout(context.Background(), caseTimeout)
+	defer cancel()
+
+	cd := create(ctx, t, r, newCard(11, "exceed"))
+
+	cancelled, cancelNow := context.WithCancel(ctx)
+	cancelNow()
+
+	t.Log("\ttest:0\tshould return the context error")
+	{
+		nc := newCard(11, "grow")
+
+		checks := []struct {
+			name string
+			err  error
+		}{
+			{"create", r.Create(cancelled, &nc, &card.Card{})},
+			{"find", second(r.Find(cancelled, cd.ID))},
+			{"list", second(r.List(cancelled, cd.UserID))},
+			{"update", r.Update(cancelled, cd.ID, &cd)},
+			{"answer", second(r.Answer(cancelled, cd.ID, true))},
+			{"delete", r.Delete(cancelled, cd.ID)},
+		}
+
+		for _, c := range checks {
+			if !errors.Is(c.err, context.Canceled) {
+				t.Errorf("unexpected %s error: %v expected: %v", c.name, c.err, context.Canceled)
+			}
+		}
+	}
+
+	t.Log("\ttest:1\tshould not change anything")
+	{
+		cards, err := r.List(ctx, cd.UserID)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if len(cards) != 1 || cards[0].Word != cd.Word || cards[0].CorrectAnswers != 0 {
+			t.Errorf("unexpected cards: %+v", cards)
+		}
+	}
+}
+
+// second drops the result and keeps the error.
+func second(_ interface{}, err error) error {
+	return err
+}
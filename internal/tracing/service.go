@@ -0,0 +1,112 @@
+package tracing
+
+import (
+	"context"
+
+	"github.com/dipress/cards/internal/card"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// CardService is a card use case.
+type CardService interface {
+	Create(ctx context.Context, f *card.Form) (*card.Card, error)
+	Import(ctx context.Context, forms []card.Form) (*card.Cards, error)
+	Find(ctx context.Context, id int) (*card.Card, error)
+	List(ctx context.Context, userID int) (*card.Cards, error)
+	Update(ctx context.Context, id int, f *card.Form) (*card.Card, error)
+	Answer(ctx context.Context, id int, f *card.AnswerForm) (*card.Card, error)
+	Delete(ctx context.Context, id int) error
+}
+
+type cardService struct {
+	next   CardService
+	tracer trace.Tracer
+}
+
+// NewCardService decorates the service with a span per method.
+func NewCardService(next CardService) CardService {
+	s := cardService{
+		next:   next,
+		tracer: otel.Tracer("github.com/dipress/cards/internal/card"),
+	}
+
+	return &s
+}
+
+func (s *cardService) start(ctx context.Context, method string, attrs ...attribute.KeyValue) (context.Context, trace.Span) {
+	return s.tracer.Start(ctx, "card.Service/"+method, trace.WithAttributes(attrs...))
+}
+
+func end(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
+
+// Create implements CardService interface.
+func (s *cardService) Create(ctx context.Context, f *card.Form) (*card.Card, error) {
+	ctx, span := s.start(ctx, "Create", attribute.Int("card.user_id", f.UserID))
+	c, err := s.next.Create(ctx, f)
+	end(span, err)
+
+	return c, err
+}
+
+// Import implements CardService interface.
+func (s *cardService) Import(ctx context.Context, forms []card.Form) (*card.Cards, error) {
+	ctx, span := s.start(ctx, "Import", attribute.Int("card.count", len(forms)))
+	cs, err := s.next.Import(ctx, forms)
+	end(span, err)
+
+	return cs, err
+}
+
+// Find implements CardService interface.
+func (s *cardService) Find(ctx context.Context, id int) (*card.Card, error) {
+	ctx, span := s.start(ctx, "Find", attribute.Int("card.id", id))
+	c, err := s.next.Find(ctx, id)
+	end(span, err)
+
+	return c, err
+}
+
+// List implements CardService interface.
+func (s *cardService) List(ctx context.Context, userID int) (*card.Cards, error) {
+	ctx, span := s.start(ctx, "List", attribute.Int("card.user_id", userID))
+	cs, err := s.next.List(ctx, userID)
+	end(span, err)
+
+	return cs, err
+}
+
+// Update implements CardService interface.
+func (s *cardService) Update(ctx context.Context, id int, f *card.Form) (*card.Card, error) {
+	ctx, span := s.start(ctx, "Update", attribute.Int("card.id", id))
+	c, err := s.next.Update(ctx, id, f)
+	end(span, err)
+
+	return c, err
+}
+
+// Answer implements CardService interface.
+func (s *cardService) Answer(ctx context.Context, id int, f *card.AnswerForm) (*card.Card, error) {
+	ctx, span := s.start(ctx, "Answer", attribute.Int("card.id", id), attribute.Bool("card.correct", f.Correct))
+	c, err := s.next.Answer(ctx, id, f)
+	end(span, err)
+
+	return c, err
+}
+
+// Delete implements CardService interface.
+func (s *cardService) Delete(ctx context.Context, id int) error {
+	ctx, span := s.start(ctx, "Delete", attribute.Int("card.id", id))
+	err := s.next.Delete(ctx, id)
+	end(span, err)
+
+	return err
+}
@@ -0,0 +1,48 @@
+package tracing
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/dipress/cards/internal/config"
+	"go.opentelemetry.io/otel"
+)
+
+func TestSetup(t *testing.T) {
+	dir, err := ioutil.TempDir("", "tracing")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer os.RemoveAll(dir)
+
+	cfg := config.Tracing{
+		Exporter:    config.ExporterFile,
+		File:        filepath.Join(dir, "spans.json"),
+		SampleRatio: 1,
+	}
+
+	shutdown, err := Setup(context.Background(), &cfg)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	_, span := otel.Tracer("test").Start(context.Background(), "test span")
+	span.End()
+
+	if err := shutdown(context.Background()); err != nil {
+		t.Fatalf("unexpected shutdown error: %v", err)
+	}
+
+	data, err := ioutil.ReadFile(cfg.File)
+	if err != nil {
+		t.Fatalf("read spans: %v", err)
+	}
+
+	if !strings.Contains(string(data), span.SpanContext().TraceID().String()) {
+		t.Errorf("expected trace id %s in exported spans:\n\t\t%s", span.SpanContext().TraceID(), data)
+	}
+}
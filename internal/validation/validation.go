@@ -4,6 +4,7 @@ import (
 	"context"
 
 	"github.com/dipress/cards/internal/card"
+	"github.com/dipress/cards/internal/i18n"
 	validation "github.com/go-ozzo/ozzo-validation"
 )
 
@@ -11,10 +12,12 @@ const (
 	validationMsg = "you have validation errors"
 )
 
-// Errors holds validation errors.
+// Errors holds validation errors, codes are
+// the stable keys of the field details.
 type Errors struct {
 	Message string            `json:"error"`
 	Details map[string]string `json:"details"`
+	Codes   map[string]string `json:"codes,omitempty"`
 }
 
 // NewErrors returns prepared errors.
@@ -22,11 +25,18 @@ func NewErrors() Errors {
 	e := Errors{
 		Message: validationMsg,
 		Details: make(map[string]string),
+		Codes:   make(map[string]string),
 	}
 
 	return e
 }
 
+// Add records the field error by its code.
+func (v Errors) Add(field, code, message string) {
+	v.Details[field] = message
+	v.Codes[field] = code
+}
+
 // Error implements error interface.
 func (v Errors) Error() string {
 	return v.Message
@@ -42,21 +52,21 @@ func (c *Card) Validate(ctx context.Context, form *card.Form) error {
 		form.Word,
 		validation.Required,
 	); err != nil {
-		ves.Details["word"] = err.Error()
+		ves.Add("word", i18n.Required, err.Error())
 	}
 
 	if err := validation.Validate(
 		form.Transcription,
 		validation.Required,
 	); err != nil {
-		ves.Details["transcription"] = err.Error()
+		ves.Add("transcription", i18n.Required, err.Error())
 	}
 
 	if err := validation.Validate(
 		form.Translation,
 		validation.Required,
 	); err != nil {
-		ves.Details["translation"] = err.Error()
+		ves.Add("translation", i18n.Required, err.Error())
 	}
 
 	if len(ves.Details) > 0 {
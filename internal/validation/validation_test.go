@@ -35,6 +35,9 @@ func TestCardValidate(t *testing.T) {
 				Details: map[string]string{
 					"word": "cannot be blank",
 				},
+				Codes: map[string]string{
+					"word": "required",
+				},
 			},
 		},
 		{
@@ -49,6 +52,9 @@ func TestCardValidate(t *testing.T) {
 				Details: map[string]string{
 					"transcription": "cannot be blank",
 				},
+				Codes: map[string]string{
+					"transcription": "required",
+				},
 			},
 		},
 		{
@@ -63,6 +69,9 @@ func TestCardValidate(t *testing.T) {
 				Details: map[string]string{
 					"translation": "cannot be blank",
 				},
+				Codes: map[string]string{
+					"translation": "required",
+				},
 			},
 		},
 	}